@@ -4,18 +4,35 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/playnet-public/mc-bot/pkg/bot"
-	"github.com/playnet-public/mc-bot/pkg/commands/players"
+	"github.com/playnet-public/mc-bot/pkg/bot/responses"
+	"github.com/playnet-public/mc-bot/pkg/capability"
+	"github.com/playnet-public/mc-bot/pkg/commands/evolution"
+	"github.com/playnet-public/mc-bot/pkg/commands/lists"
+	"github.com/playnet-public/mc-bot/pkg/commands/properties"
+	"github.com/playnet-public/mc-bot/pkg/commands/resources"
 	"github.com/playnet-public/mc-bot/pkg/commands/restart"
-	"github.com/playnet-public/mc-bot/pkg/commands/wakeup"
-	"github.com/playnet-public/mc-bot/pkg/commands/whitelist"
-	"github.com/playnet-public/mc-bot/pkg/commands/winddown"
+	"github.com/playnet-public/mc-bot/pkg/docker"
+	"github.com/playnet-public/mc-bot/pkg/factorio"
+	"github.com/playnet-public/mc-bot/pkg/game"
+	"github.com/playnet-public/mc-bot/pkg/idle"
 	"github.com/playnet-public/mc-bot/pkg/kubernetes"
 	"github.com/playnet-public/mc-bot/pkg/minecraft"
-	"github.com/playnet-public/mc-bot/pkg/noop"
-	"github.com/playnet-public/mc-bot/pkg/operands/rcon"
+	"github.com/playnet-public/mc-bot/pkg/palworld"
+	"github.com/playnet-public/mc-bot/pkg/process"
+	"github.com/playnet-public/mc-bot/pkg/pterodactyl"
+	"github.com/playnet-public/mc-bot/pkg/rcongame"
+	"github.com/playnet-public/mc-bot/pkg/satisfactory"
+	"github.com/playnet-public/mc-bot/pkg/steam"
+	"github.com/playnet-public/mc-bot/pkg/terraria"
 	"github.com/playnet-public/mc-bot/pkg/valheim"
 	"github.com/seibert-media/golibs/log"
 	"go.uber.org/zap"
@@ -24,13 +41,26 @@ import (
 	"k8s.io/client-go/rest"
 )
 
+// games which can be enabled, each installing its specific commands and returning its server
+// for the registry, which installs the commands supported by the server.
+// Steam query games are enabled by steamGames instead, as several of them can be configured
+var games = []struct {
+	env    string
+	enable func(ctx context.Context, bot bot.Service) (bot.Service, game.Server)
+}{
+	{env: "ENABLE_MINECRAFT", enable: enableMinecraft},
+	{env: "ENABLE_VALHEIM", enable: enableValheim},
+	{env: "ENABLE_RCON", enable: enableRCON},
+	{env: "ENABLE_FACTORIO", enable: enableFactorio},
+	{env: "ENABLE_TERRARIA", enable: enableTerraria},
+	{env: "ENABLE_PALWORLD", enable: enablePalworld},
+	{env: "ENABLE_SATISFACTORY", enable: enableSatisfactory},
+}
+
 func main() {
 	token := os.Getenv("TOKEN")
 	appID := os.Getenv("APP_ID")
 
-	minecraftEnabled := os.Getenv("ENABLE_MINECRAFT")
-	valheimEnabled := os.Getenv("ENABLE_VALHEIM")
-
 	logger, err := log.New("", true)
 	if err != nil {
 		fmt.Println(err)
@@ -45,26 +75,84 @@ func main() {
 
 	bot := bot.NewMulti(appID)
 
-	if len(minecraftEnabled) > 0 {
-		bot = enableMinecraft(ctx, bot)
+	registry := game.NewRegistry()
+	for _, g := range games {
+		if len(os.Getenv(g.env)) < 1 {
+			continue
+		}
+		var server game.Server
+		bot, server = g.enable(ctx, bot)
+		registry = registry.Register(server)
 	}
-
-	if len(valheimEnabled) > 0 {
-		bot = enableValheim(ctx, bot)
+	for _, prefix := range steamGames() {
+		var server game.Server
+		bot, server = enableSteam(ctx, bot, prefix)
+		registry = registry.Register(server)
 	}
+	bot = registry.Install(ctx, bot)
 
-	if err := bot.Finalize(ctx, app.Session()); err != nil {
-		log.From(ctx).Fatal("finalizing bot", zap.Error(err))
+	// only the leader opens the gateway and runs the runners, which are started by Finalize
+	run := func(ctx context.Context) error {
+		if err := bot.Finalize(ctx, app.Session()); err != nil {
+			return fmt.Errorf("finalizing bot: %w", err)
+		}
+		return app.Start(ctx)
 	}
 
 	defer app.Stop(ctx)
-	if err := app.Start(ctx); err != nil {
+	if elector := setupLeaderElection(ctx); elector != nil {
+		ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		err = elector.Run(ctx, run)
+	} else {
+		err = run(ctx)
+	}
+	if err != nil {
 		log.From(ctx).Fatal("running bot", zap.Error(err))
 	}
 }
 
-func setupKubernetesClient() (*kubernetesClient.Clientset, error) {
-	config, err := rest.InClusterConfig()
+// setupLeaderElection from the environment variables, returning nil if it's disabled
+func setupLeaderElection(ctx context.Context) *kubernetes.LeaderElector {
+	lease := os.Getenv("LEADER_ELECTION_LEASE")
+	if len(lease) < 1 {
+		return nil
+	}
+
+	clientset, err := setupKubernetesClient()
+	if err != nil {
+		log.From(ctx).Fatal("setting up kubernetes client", zap.Error(err))
+	}
+
+	identity := os.Getenv("POD_NAME")
+	if len(identity) < 1 {
+		identity, err = os.Hostname()
+		if err != nil {
+			log.From(ctx).Fatal("getting leader election identity", zap.Error(err))
+		}
+	}
+
+	elector := &kubernetes.LeaderElector{
+		Namespace: os.Getenv("LEADER_ELECTION_NAMESPACE"),
+		Name:      lease,
+		Identity:  identity,
+		ClientSet: clientset,
+	}
+	if duration := os.Getenv("LEADER_ELECTION_LEASE_DURATION"); len(duration) > 0 {
+		d, err := time.ParseDuration(duration)
+		if err != nil {
+			log.From(ctx).Fatal("parsing leader election lease duration", zap.Error(err))
+		}
+		// keep the ratios of the default timings
+		elector.LeaseDuration = d
+		elector.RenewDeadline = d * 2 / 3
+		elector.RetryPeriod = d * 2 / 15
+	}
+	return elector
+}
+
+func setupKubernetesClient() (kubernetesClient.Interface, error) {
+	config, err := setupKubernetesConfig()
 	if err != nil {
 		return nil, err
 	}
@@ -77,100 +165,770 @@ func setupKubernetesClient() (*kubernetesClient.Clientset, error) {
 	return clientset, nil
 }
 
-func enableMinecraft(ctx context.Context, bot bot.Service) bot.Service {
+// setupRestartVote from the environment variables using prefix, returning nil if voting is disabled
+func setupRestartVote(ctx context.Context, prefix string, playerLister interface {
+	Players(ctx context.Context) (int, []string, error)
+}) *restart.Vote {
+	if len(os.Getenv(prefix+"_RESTART_VOTE")) < 1 {
+		return nil
+	}
+
+	vote := &restart.Vote{
+		Timeout: 10 * time.Minute,
+	}
+
+	if quorum := os.Getenv(prefix + "_RESTART_VOTE_QUORUM"); len(quorum) > 0 {
+		q, err := strconv.Atoi(quorum)
+		if err != nil {
+			log.From(ctx).Fatal("parsing restart vote quorum", zap.Error(err))
+		}
+		vote.Quorum = q
+	}
+
+	if timeout := os.Getenv(prefix + "_RESTART_VOTE_TIMEOUT"); len(timeout) > 0 {
+		t, err := time.ParseDuration(timeout)
+		if err != nil {
+			log.From(ctx).Fatal("parsing restart vote timeout", zap.Error(err))
+		}
+		vote.Timeout = t
+	}
+
+	if len(os.Getenv(prefix+"_RESTART_VOTE_VERIFY_PLAYERS")) > 0 && playerLister != nil {
+		vote.PlayerLister = playerLister
+	}
+
+	return vote
+}
+
+// setupRestartScheduler sets the delay of scheduler from the environment variables using prefix
+func setupRestartScheduler(ctx context.Context, prefix string, scheduler restart.Scheduler) *restart.Scheduler {
+	if delay := os.Getenv(prefix + "_RESTART_DELAY"); len(delay) > 0 {
+		d, err := time.ParseDuration(delay)
+		if err != nil {
+			log.From(ctx).Fatal("parsing restart delay", zap.String("prefix", prefix), zap.Error(err))
+		}
+		scheduler.Delay = d
+	}
+	return &scheduler
+}
+
+// setupKubernetesConfig uses the in-cluster config when running in a cluster without
+// KUBECONFIG or KUBE_CONTEXT being set and falls back to the default kubeconfig otherwise
+func setupKubernetesConfig() (*rest.Config, error) {
+	return kubernetes.Config(os.Getenv("KUBE_CONTEXT"))
+}
+
+const (
+	restartModeRCON = "rcon"
+	fieldManager    = "minecraft-bot"
+)
+
+// setupScaler from the environment variables using prefix, returning nil if no backend is configured
+func setupScaler(ctx context.Context, prefix string) capability.Backend {
+	if panel := setupPterodactyl(prefix); panel != nil {
+		return panel
+	}
+	if docker := setupDocker(ctx, prefix); docker != nil {
+		return docker
+	}
+	if workload := setupWorkload(ctx, prefix); workload != nil {
+		return workload
+	}
+	return nil
+}
+
+// setupResourceAlert from the environment variables using prefix, returning nil if no channel is configured
+func setupResourceAlert(ctx context.Context, prefix string) *resources.Alert {
+	channelID := os.Getenv(prefix + "_RESOURCE_ALERT_CHANNEL_ID")
+	if len(channelID) < 1 {
+		return nil
+	}
+	threshold := 0.9
+	if value := os.Getenv(prefix + "_RESOURCE_ALERT_THRESHOLD"); len(value) > 0 {
+		t, err := strconv.ParseFloat(value, 64)
+		if err != nil || t <= 0 || t > 1 {
+			log.From(ctx).Fatal("parsing resource alert threshold, it has to be between 0 and 1", zap.String("threshold", value), zap.Error(err))
+		}
+		threshold = t
+	}
+	return &resources.Alert{
+		ChannelID: channelID,
+		Interval:  1 * time.Minute,
+		Threshold: threshold,
+	}
+}
+
+// setupIdleWatcher from the environment variables using prefix, returning nil if no idle timeout is configured
+func setupIdleWatcher(ctx context.Context, prefix string) *idle.Watcher {
+	timeout := os.Getenv(prefix + "_IDLE_TIMEOUT")
+	if len(timeout) < 1 {
+		return nil
+	}
+	t, err := time.ParseDuration(timeout)
+	if err != nil {
+		log.From(ctx).Fatal("parsing idle timeout", zap.String("prefix", prefix), zap.Error(err))
+	}
+	return &idle.Watcher{
+		ChannelID: os.Getenv(prefix + "_IDLE_CHANNEL_ID"),
+		Interval:  1 * time.Minute,
+		Timeout:   t,
+	}
+}
+
+// setupProperties from the environment variables using prefix, returning nil if no
+// ConfigMap or file is configured
+func setupProperties(ctx context.Context, prefix string) interface {
+	Load(ctx context.Context) (string, error)
+	Save(ctx context.Context, content string) error
+} {
+	if path := os.Getenv(prefix + "_PROPERTIES_FILE"); len(path) > 0 {
+		return minecraft.PropertiesFile{Path: path}
+	}
+
+	name := os.Getenv(prefix + "_PROPERTIES_CONFIGMAP")
+	if len(name) < 1 {
+		return nil
+	}
+	key := os.Getenv(prefix + "_PROPERTIES_KEY")
+	if len(key) < 1 {
+		key = "server.properties"
+	}
+
+	clientset, err := setupKubernetesClient()
+	if err != nil {
+		log.From(ctx).Fatal("setting up kubernetes client", zap.Error(err))
+	}
+
+	return kubernetes.ConfigMapFile{
+		Namespace:    os.Getenv(prefix + "_PROPERTIES_NAMESPACE"),
+		Name:         name,
+		Key:          key,
+		ClientSet:    clientset,
+		FieldManager: fieldManager,
+	}
+}
+
+// setupValheimLists from the environment variables, returning no stores if neither
+// a directory nor a ConfigMap is configured
+func setupValheimLists(ctx context.Context) map[valheim.List]lists.Store {
+	stores := map[valheim.List]lists.Store{}
+	all := []valheim.List{valheim.AdminList, valheim.BannedList, valheim.PermittedList}
+
+	if dir := os.Getenv("VALHEIM_LISTS_DIR"); len(dir) > 0 {
+		for _, list := range all {
+			stores[list] = valheim.ListFile{Path: filepath.Join(dir, string(list))}
+		}
+		return stores
+	}
+
+	name := os.Getenv("VALHEIM_LISTS_CONFIGMAP")
+	if len(name) < 1 {
+		return stores
+	}
+
+	clientset, err := setupKubernetesClient()
+	if err != nil {
+		log.From(ctx).Fatal("setting up kubernetes client", zap.Error(err))
+	}
+	for _, list := range all {
+		stores[list] = kubernetes.ConfigMapFile{
+			Namespace:    os.Getenv("VALHEIM_LISTS_NAMESPACE"),
+			Name:         name,
+			Key:          string(list),
+			ClientSet:    clientset,
+			FieldManager: fieldManager,
+			Optional:     true,
+		}
+	}
+	return stores
+}
+
+// setupSteamClient configures client from the environment variables using prefix and sets it up
+func setupSteamClient(ctx context.Context, prefix string, client steam.Client) steam.Client {
+	if timeout := os.Getenv(prefix + "_QUERY_TIMEOUT"); len(timeout) > 0 {
+		t, err := time.ParseDuration(timeout)
+		if err != nil {
+			log.From(ctx).Fatal("parsing query timeout", zap.Error(err))
+		}
+		client = client.WithTimeout(t)
+	}
+	if size := os.Getenv(prefix + "_QUERY_MAX_PACKET_SIZE"); len(size) > 0 {
+		s, err := strconv.ParseUint(size, 10, 32)
+		if err != nil {
+			log.From(ctx).Fatal("parsing query max packet size", zap.Error(err))
+		}
+		client = client.WithMaxPacketSize(uint32(s))
+	}
+
+	client, err := client.Setup()
+	if err != nil {
+		log.From(ctx).Fatal("setting up steam query client", zap.String("game", prefix), zap.Error(err))
+	}
+	return client
+}
+
+// setupRCONTemplates from the preset selected by the environment variables using prefix,
+// overriding single commands and regular expressions if set
+func setupRCONTemplates(ctx context.Context, prefix string) rcongame.Templates {
+	templates := rcongame.Templates{}
+	if preset := os.Getenv(prefix + "_PRESET"); len(preset) > 0 {
+		var ok bool
+		templates, ok = rcongame.Presets[strings.ToLower(preset)]
+		if !ok {
+			log.From(ctx).Fatal("unknown rcon preset", zap.String("preset", preset))
+		}
+	}
+
+	for env, template := range map[string]*string{
+		"_LIST_COMMAND":      &templates.List,
+		"_SAY_COMMAND":       &templates.Say,
+		"_KICK_COMMAND":      &templates.Kick,
+		"_SAVE_COMMAND":      &templates.Save,
+		"_WHITELIST_COMMAND": &templates.Whitelist,
+		"_RESTART_COMMAND":   &templates.Restart,
+	} {
+		if value, ok := os.LookupEnv(prefix + env); ok {
+			*template = value
+		}
+	}
+	for env, regex := range map[string]**regexp.Regexp{
+		"_PLAYER_COUNT_REGEX": &templates.PlayerCount,
+		"_PLAYER_REGEX":       &templates.Player,
+	} {
+		if value := os.Getenv(prefix + env); len(value) > 0 {
+			r, err := regexp.Compile(value)
+			if err != nil {
+				log.From(ctx).Fatal("parsing rcon regex", zap.String("env", prefix+env), zap.Error(err))
+			}
+			*regex = r
+		}
+	}
+
+	if err := templates.Validate(); err != nil {
+		log.From(ctx).Fatal("validating rcon templates", zap.Error(err))
+	}
+	return templates
+}
+
+// setupProcess from the environment variables using prefix, returning nil if no command is configured
+func setupProcess(ctx context.Context, prefix string, defaultStopCommand string, defaultResponsePattern *regexp.Regexp) *process.Supervisor {
+	command := strings.Fields(os.Getenv(prefix + "_PROCESS_COMMAND"))
+	if len(command) < 1 {
+		return nil
+	}
+
+	stopCommand := os.Getenv(prefix + "_PROCESS_STOP_COMMAND")
+	if len(stopCommand) < 1 {
+		stopCommand = defaultStopCommand
+	}
+
+	killTimeout := 30 * time.Second
+	if timeout := os.Getenv(prefix + "_PROCESS_KILL_TIMEOUT"); len(timeout) > 0 {
+		t, err := time.ParseDuration(timeout)
+		if err != nil {
+			log.From(ctx).Fatal("parsing process kill timeout", zap.Error(err))
+		}
+		killTimeout = t
+	}
+
+	responsePattern := defaultResponsePattern
+	if pattern := os.Getenv(prefix + "_PROCESS_RESPONSE_PATTERN"); len(pattern) > 0 {
+		var err error
+		responsePattern, err = regexp.Compile(pattern)
+		if err != nil {
+			log.From(ctx).Fatal("parsing process response pattern", zap.Error(err))
+		}
+	}
+
+	return process.NewSupervisor(command, os.Getenv(prefix+"_PROCESS_DIR"), stopCommand, killTimeout).
+		WithResponsePattern(responsePattern)
+}
+
+// setupPterodactyl from the environment variables using prefix, returning nil if no panel is configured
+func setupPterodactyl(prefix string) *pterodactyl.Client {
+	panelURL := os.Getenv(prefix + "_PTERODACTYL_URL")
+	if len(panelURL) < 1 {
+		return nil
+	}
+
+	messageCommand := os.Getenv(prefix + "_PTERODACTYL_MESSAGE_COMMAND")
+	if len(messageCommand) < 1 {
+		messageCommand = "say"
+	}
+
+	client := pterodactyl.NewClient(
+		panelURL,
+		os.Getenv(prefix+"_PTERODACTYL_API_KEY"),
+		os.Getenv(prefix+"_PTERODACTYL_SERVER"),
+		messageCommand,
+	)
+	return &client
+}
+
+// setupDocker from the environment variables using prefix, returning nil if no container is configured
+func setupDocker(ctx context.Context, prefix string) *docker.Client {
+	container := os.Getenv(prefix + "_DOCKER_CONTAINER")
+	if len(container) < 1 {
+		return nil
+	}
+
+	socket := os.Getenv(prefix + "_DOCKER_SOCKET")
+	if len(socket) < 1 {
+		socket = docker.DefaultSocket
+	}
+
+	stopTimeout := 30 * time.Second
+	if timeout := os.Getenv(prefix + "_DOCKER_STOP_TIMEOUT"); len(timeout) > 0 {
+		t, err := time.ParseDuration(timeout)
+		if err != nil {
+			log.From(ctx).Fatal("parsing docker stop timeout", zap.Error(err))
+		}
+		stopTimeout = t
+	}
+
+	client := docker.NewClient(socket, container, stopTimeout)
+	return &client
+}
+
+// setupWorkload from the environment variables using prefix, returning nil if no workload is configured.
+// PREFIX_STS_NAME and PREFIX_STS_NAMESPACE are supported for compatibility.
+func setupWorkload(ctx context.Context, prefix string) *kubernetes.Workload {
+	name := os.Getenv(prefix + "_WORKLOAD_NAME")
+	if len(name) < 1 {
+		name = os.Getenv(prefix + "_STS_NAME")
+	}
+	namespace := os.Getenv(prefix + "_WORKLOAD_NAMESPACE")
+	if len(namespace) < 1 {
+		namespace = os.Getenv(prefix + "_STS_NAMESPACE")
+	}
+	if len(name) < 1 || len(namespace) < 1 {
+		return nil
+	}
+
+	resource, err := kubernetes.ParseResource(os.Getenv(prefix + "_WORKLOAD_KIND"))
+	if err != nil {
+		log.From(ctx).Fatal("parsing workload kind", zap.Error(err))
+	}
+
+	clientset, err := setupKubernetesClient()
+	if err != nil {
+		log.From(ctx).Fatal("setting up kubernetes client", zap.Error(err))
+	}
+
+	return &kubernetes.Workload{
+		Namespace:    namespace,
+		Name:         name,
+		Resource:     resource,
+		ClientSet:    clientset,
+		FieldManager: fieldManager,
+		VersionEnv:   os.Getenv(prefix + "_WORKLOAD_VERSION_ENV"),
+	}
+}
+
+func enableMinecraft(ctx context.Context, bot bot.Service) (bot.Service, game.Server) {
 	minecraftApproverRole := os.Getenv("MC_APPROVERS")
 	minecraftRconAddress := os.Getenv("MC_RCON_ADDRESS")
 	minecraftRconPassword := os.Getenv("MC_RCON_PASSWORD")
 	minecraftRCONChannelID := os.Getenv("MC_RCON_CHANNEL_ID")
-	minecraftStatefulSetName := os.Getenv("MC_STS_NAME")
-	minecraftStatefulSetNamespace := os.Getenv("MC_STS_NAMESPACE")
+	minecraftRestartMode := os.Getenv("MC_RESTART_MODE")
+	minecraftAddress := os.Getenv("MC_ADDRESS")
+	minecraftIdleChannelID := os.Getenv("MC_IDLE_CHANNEL_ID")
+	minecraftWakeListenAddress := os.Getenv("MC_WAKE_LISTEN_ADDRESS")
+	minecraftWakeMOTD := os.Getenv("MC_WAKE_MOTD")
+	minecraftWakeupTimeout := os.Getenv("MC_WAKEUP_TIMEOUT")
 
-	mc, err := minecraft.NewClient().Setup(minecraftRconAddress, minecraftRconPassword)
-	if err != nil {
-		log.From(ctx).Error("setting up minecraft client", zap.Error(err))
+	mc := minecraft.NewClient()
+	supervisor := setupProcess(ctx, "MC", "stop", minecraft.ConsolePattern)
+	panel := setupPterodactyl("MC")
+	if supervisor != nil {
+		mc = mc.WithCommandSender(supervisor)
+		bot = bot.WithRunner(supervisor)
+	} else if panel != nil && len(minecraftRconAddress) < 1 {
+		// the panel doesn't return command output, so RCON is preferred if available
+		// and players are listed using the server list ping otherwise
+		if len(minecraftAddress) < 1 {
+			log.From(ctx).Fatal("listing players through the panel requires MC_ADDRESS or MC_RCON_ADDRESS")
+		}
+		mc = mc.WithCommandSender(panel).WithPlayerLister(minecraft.Pinger{Address: minecraftAddress})
+	} else {
+		var err error
+		mc, err = mc.Setup(minecraftRconAddress, minecraftRconPassword)
+		if err != nil {
+			log.From(ctx).Error("setting up minecraft client", zap.Error(err))
+		}
 	}
 
-	bot = bot.WithCommand(whitelist.Command{
-		ApproverRole: minecraftApproverRole,
-		Whitelister:  mc,
-	})
-	bot = bot.WithCommand(restart.Command{
-		OverriderRole: minecraftApproverRole,
-		PlayerCounter: mc,
-		Restarter:     mc,
-		MessageSender: mc,
-	})
-	bot = bot.WithCommand(players.Command{
-		PlayerLister: mc,
-		PollInterval: 10 * time.Second,
-	})
+	server := game.Server{
+		Name:          "minecraft",
+		ApproverRole:  minecraftApproverRole,
+		RCONChannelID: minecraftRCONChannelID,
+		Client:        mc,
+		Vote:          setupRestartVote(ctx, "MC", mc),
+		ResourceAlert: setupResourceAlert(ctx, "MC"),
+		IdleWatcher:   setupIdleWatcher(ctx, "MC"),
+	}
+	if len(minecraftAddress) > 0 {
+		server.Versioner = minecraft.Pinger{Address: minecraftAddress}
+	}
 
-	bot = bot.WithOperand(rcon.Operand{
-		ChannelID:     minecraftRCONChannelID,
-		RCONRole:      minecraftApproverRole,
-		CommandSender: mc,
-	})
+	// the RCON restart command is only supported by Spigot and its forks
+	var restarter capability.Restarter = mc
 
-	if len(minecraftStatefulSetName) > 0 && len(minecraftStatefulSetNamespace) > 0 {
-		clientset, err := setupKubernetesClient()
-		if err != nil {
-			log.From(ctx).Fatal("setting up kubernetes client", zap.Error(err))
+	scaler := setupScaler(ctx, "MC")
+	if supervisor != nil {
+		scaler = supervisor
+	}
+	if scaler == nil && len(minecraftRestartMode) > 0 && minecraftRestartMode != restartModeRCON {
+		log.From(ctx).Fatal("restart mode requires a backend, e.g. MC_WORKLOAD_NAME", zap.String("mode", minecraftRestartMode))
+	}
+	if scaler != nil {
+		server.Backend = scaler
+		// the supervisor restarts any server, so it's only bypassed if the RCON mode is requested explicitly
+		if (len(minecraftRestartMode) > 0 || supervisor != nil) && minecraftRestartMode != restartModeRCON {
+			restarter = scaler
 		}
 
-		scaler := kubernetes.StatefulSetScaler{
-			Namespace:    minecraftStatefulSetNamespace,
-			Name:         minecraftStatefulSetName,
-			ClientSet:    clientset,
-			FieldManager: "minecraft-bot",
+		if len(minecraftWakeupTimeout) > 0 {
+			var err error
+			server.Timeout, err = time.ParseDuration(minecraftWakeupTimeout)
+			if err != nil {
+				log.From(ctx).Fatal("parsing wakeup timeout", zap.Error(err))
+			}
+			if server.Timeout > responses.MaxTimeout {
+				log.From(ctx).Warn("capping wakeup timeout", zap.Duration("timeout", server.Timeout), zap.Duration("max", responses.MaxTimeout))
+			}
 		}
 
-		bot = bot.WithCommand(winddown.Command{
-			OverriderRole: minecraftApproverRole,
+		if len(minecraftWakeListenAddress) > 0 {
+			bot = bot.WithRunner(minecraft.WakeListener{
+				Address:   minecraftWakeListenAddress,
+				Interval:  10 * time.Second,
+				MOTD:      minecraftWakeMOTD,
+				ChannelID: minecraftIdleChannelID,
+				Scaler:    scaler,
+			})
+		}
+	}
+	server.Restarter = restarter
+
+	if store := setupProperties(ctx, "MC"); store != nil {
+		scheduler := setupRestartScheduler(ctx, "MC_PROPERTIES", restart.Scheduler{
+			Restarter:     restarter,
 			PlayerCounter: mc,
-			Scaler:        scaler,
 			MessageSender: mc,
 		})
-
-		bot = bot.WithCommand(wakeup.Command{
-			Scaler: scaler,
+		bot = bot.WithCommand(properties.Command{
+			ApproverRole: minecraftApproverRole,
+			Store:        store,
+			Scheduler:    scheduler,
 		})
 	}
 
-	return bot
+	return bot, server
 }
 
-func enableValheim(ctx context.Context, bot bot.Service) bot.Service {
+func enableValheim(ctx context.Context, bot bot.Service) (bot.Service, game.Server) {
 	valheimQueryAddress := os.Getenv("VALHEIM_QUERY_ADDRESS")
 	valheimApproverRole := os.Getenv("VALHEIM_APPROVERS")
 	valheimServerNamespace := os.Getenv("VALHEIM_SERVER_NAMESPACE")
 	valheimServerPodLabel := os.Getenv("VALHEIM_POD_LABEL")
 	valheimServerPodLabelKey := os.Getenv("VALHEIM_POD_LABEL_KEY")
 
-	valheimClient, err := valheim.NewClient(valheimQueryAddress).Setup()
-	if err != nil {
-		log.From(ctx).Fatal("setting up valheim client", zap.Error(err))
-	}
+	valheimClient := setupSteamClient(ctx, "VALHEIM", valheim.NewClient(valheimQueryAddress))
 
-	clientset, err := setupKubernetesClient()
-	if err != nil {
-		log.From(ctx).Fatal("setting up kubernetes client", zap.Error(err))
+	server := game.Server{
+		Name:          "valheim",
+		ApproverRole:  valheimApproverRole,
+		Client:        valheimClient,
+		Vote:          setupRestartVote(ctx, "VALHEIM", valheimClient),
+		ResourceAlert: setupResourceAlert(ctx, "VALHEIM"),
+		IdleWatcher:   setupIdleWatcher(ctx, "VALHEIM"),
 	}
 
-	bot = bot.WithCommand(restart.Command{
-		OverriderRole: valheimApproverRole,
-		PlayerCounter: valheimClient,
-		Restarter: kubernetes.PodRestarter{
+	var restarter capability.Restarter
+	if scaler := setupScaler(ctx, "VALHEIM"); scaler != nil {
+		restarter = scaler
+	} else {
+		clientset, err := setupKubernetesClient()
+		if err != nil {
+			log.From(ctx).Fatal("setting up kubernetes client", zap.Error(err))
+		}
+		podRestarter := kubernetes.PodRestarter{
 			Namespace:  valheimServerNamespace,
 			LabelKey:   valheimServerPodLabelKey,
 			LabelValue: valheimServerPodLabel,
 			ClientSet:  clientset,
-		},
-		MessageSender: noop.MessageSender{},
-	})
-	bot = bot.WithCommand(players.Command{
-		PlayerLister: valheimClient,
-		PollInterval: 10 * time.Second,
+		}
+		if gracePeriod := os.Getenv("VALHEIM_POD_GRACE_PERIOD"); len(gracePeriod) > 0 {
+			podRestarter.GracePeriod, err = time.ParseDuration(gracePeriod)
+			if err != nil {
+				log.From(ctx).Fatal("parsing pod grace period", zap.Error(err))
+			}
+		}
+		if timeout := os.Getenv("VALHEIM_RESTART_TIMEOUT"); len(timeout) > 0 {
+			podRestarter.Timeout, err = time.ParseDuration(timeout)
+			if err != nil {
+				log.From(ctx).Fatal("parsing restart timeout", zap.Error(err))
+			}
+			if podRestarter.Timeout > responses.MaxTimeout {
+				log.From(ctx).Warn("capping restart timeout", zap.Duration("timeout", podRestarter.Timeout), zap.Duration("max", responses.MaxTimeout))
+			}
+		}
+		restarter = podRestarter
+	}
+	server.Backend = restarter
+
+	if stores := setupValheimLists(ctx); len(stores) > 0 {
+		scheduler := setupRestartScheduler(ctx, "VALHEIM_LISTS", restart.Scheduler{
+			Restarter:     restarter,
+			PlayerCounter: valheimClient,
+		})
+		bot = bot.WithCommand(lists.Command{
+			ApproverRole: valheimApproverRole,
+			Stores:       stores,
+			Scheduler:    scheduler,
+		})
+	}
+
+	return bot, server
+}
+
+// steamGames returns the prefixes of the environment variables configuring the games enabled by ENABLE_STEAM,
+// which lists them separated by commas, e.g. "RUST,ARK". Setting it to true enables a single game using STEAM
+func steamGames() []string {
+	enabled := os.Getenv("ENABLE_STEAM")
+	if len(enabled) < 1 {
+		return nil
+	}
+	if enabled == "true" {
+		return []string{"STEAM"}
+	}
+
+	prefixes := []string{}
+	for _, prefix := range strings.Split(enabled, ",") {
+		if prefix = strings.ToUpper(strings.TrimSpace(prefix)); len(prefix) > 0 {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	return prefixes
+}
+
+// enableSteam for any game answering Steam server queries, e.g. Rust, ARK or Project Zomboid,
+// configured by the environment variables using prefix and named after it
+func enableSteam(ctx context.Context, bot bot.Service, prefix string) (bot.Service, game.Server) {
+	steamQueryAddress := os.Getenv(prefix + "_QUERY_ADDRESS")
+	steamApproverRole := os.Getenv(prefix + "_APPROVERS")
+
+	steamClient := setupSteamClient(ctx, prefix, steam.NewClient(steamQueryAddress))
+
+	server := game.Server{
+		Name:          strings.ToLower(prefix),
+		ApproverRole:  steamApproverRole,
+		Client:        steamClient,
+		Vote:          setupRestartVote(ctx, prefix, steamClient),
+		ResourceAlert: setupResourceAlert(ctx, prefix),
+		IdleWatcher:   setupIdleWatcher(ctx, prefix),
+	}
+	if scaler := setupScaler(ctx, prefix); scaler != nil {
+		server.Backend = scaler
+	}
+
+	return bot, server
+}
+
+// enableRCON for any game speaking Source RCON, e.g. Rust, ARK, Squad or CS2
+func enableRCON(ctx context.Context, bot bot.Service) (bot.Service, game.Server) {
+	rconAddress := os.Getenv("RCON_ADDRESS")
+	rconPassword := os.Getenv("RCON_PASSWORD")
+	rconApproverRole := os.Getenv("RCON_APPROVERS")
+	rconChannelID := os.Getenv("RCON_CHANNEL_ID")
+
+	templates := setupRCONTemplates(ctx, "RCON")
+	client, err := rcongame.NewClient(templates).Setup(rconAddress, rconPassword)
+	if err != nil {
+		log.From(ctx).Error("setting up rcon client", zap.Error(err))
+	}
+
+	// the client declares which commands its templates support
+	server := game.Server{
+		Name:          "rcon",
+		ApproverRole:  rconApproverRole,
+		RCONChannelID: rconChannelID,
+		Client:        client,
+		Vote:          setupRestartVote(ctx, "RCON", client),
+		ResourceAlert: setupResourceAlert(ctx, "RCON"),
+		IdleWatcher:   setupIdleWatcher(ctx, "RCON"),
+	}
+	if scaler := setupScaler(ctx, "RCON"); scaler != nil {
+		server.Backend = scaler
+		if len(templates.Save) > 0 {
+			server.Restarter = savingRestarter{saver: client, restarter: scaler}
+		}
+	}
+
+	return bot, server
+}
+
+func enableFactorio(ctx context.Context, bot bot.Service) (bot.Service, game.Server) {
+	factorioApproverRole := os.Getenv("FACTORIO_APPROVERS")
+	factorioRconAddress := os.Getenv("FACTORIO_RCON_ADDRESS")
+	factorioRconPassword := os.Getenv("FACTORIO_RCON_PASSWORD")
+	factorioRCONChannelID := os.Getenv("FACTORIO_RCON_CHANNEL_ID")
+
+	factorioClient, err := factorio.NewClient().Setup(factorioRconAddress, factorioRconPassword)
+	if err != nil {
+		log.From(ctx).Error("setting up factorio client", zap.Error(err))
+	}
+
+	bot = bot.WithCommand(evolution.Command{
+		Reporter: factorioClient,
 	})
 
-	return bot
+	server := game.Server{
+		Name:          "factorio",
+		ApproverRole:  factorioApproverRole,
+		RCONChannelID: factorioRCONChannelID,
+		Client:        factorioClient,
+		Vote:          setupRestartVote(ctx, "FACTORIO", factorioClient),
+		ResourceAlert: setupResourceAlert(ctx, "FACTORIO"),
+		IdleWatcher:   setupIdleWatcher(ctx, "FACTORIO"),
+	}
+
+	// Factorio can't restart itself, so restarts require a backend
+	if scaler := setupScaler(ctx, "FACTORIO"); scaler != nil {
+		server.Backend = scaler
+		server.Restarter = savingRestarter{saver: factorioClient, restarter: scaler}
+	}
+
+	return bot, server
+}
+
+func enableTerraria(ctx context.Context, bot bot.Service) (bot.Service, game.Server) {
+	terrariaApproverRole := os.Getenv("TERRARIA_APPROVERS")
+	terrariaRestURL := os.Getenv("TERRARIA_REST_URL")
+	terrariaRestToken := os.Getenv("TERRARIA_REST_TOKEN")
+	terrariaRestUsername := os.Getenv("TERRARIA_REST_USERNAME")
+	terrariaRestPassword := os.Getenv("TERRARIA_REST_PASSWORD")
+	terrariaRCONChannelID := os.Getenv("TERRARIA_RCON_CHANNEL_ID")
+
+	terrariaClient := terraria.NewClient(terrariaRestURL, terrariaRestToken)
+	if len(terrariaRestUsername) > 0 {
+		terrariaClient = terrariaClient.WithCredentials(terrariaRestUsername, terrariaRestPassword)
+	}
+
+	server := game.Server{
+		Name:          "terraria",
+		ApproverRole:  terrariaApproverRole,
+		RCONChannelID: terrariaRCONChannelID,
+		Client:        terrariaClient,
+		Vote:          setupRestartVote(ctx, "TERRARIA", terrariaClient),
+		ResourceAlert: setupResourceAlert(ctx, "TERRARIA"),
+		IdleWatcher:   setupIdleWatcher(ctx, "TERRARIA"),
+	}
+
+	// the REST API can only shut the server down, so restarts require a backend
+	if scaler := setupScaler(ctx, "TERRARIA"); scaler != nil {
+		server.Backend = scaler
+		server.Restarter = savingRestarter{saver: terrariaClient, restarter: scaler}
+	}
+
+	return bot, server
+}
+
+func enablePalworld(ctx context.Context, bot bot.Service) (bot.Service, game.Server) {
+	palworldApproverRole := os.Getenv("PALWORLD_APPROVERS")
+	palworldAPIURL := os.Getenv("PALWORLD_API_URL")
+	palworldAdminPassword := os.Getenv("PALWORLD_ADMIN_PASSWORD")
+
+	palworldClient := palworld.NewClient(palworldAPIURL, palworldAdminPassword)
+	if delay := os.Getenv("PALWORLD_SHUTDOWN_DELAY"); len(delay) > 0 {
+		shutdownDelay, err := time.ParseDuration(delay)
+		if err != nil {
+			log.From(ctx).Fatal("parsing palworld shutdown delay", zap.Error(err))
+		}
+		palworldClient = palworldClient.WithShutdownDelay(shutdownDelay)
+	}
+
+	// without a backend the server shuts down gracefully and relies on its container being restarted
+	server := game.Server{
+		Name:          "palworld",
+		ApproverRole:  palworldApproverRole,
+		Client:        palworldClient,
+		Restarter:     palworldClient,
+		Vote:          setupRestartVote(ctx, "PALWORLD", palworldClient),
+		ResourceAlert: setupResourceAlert(ctx, "PALWORLD"),
+		IdleWatcher:   setupIdleWatcher(ctx, "PALWORLD"),
+	}
+	if scaler := setupScaler(ctx, "PALWORLD"); scaler != nil {
+		server.Backend = scaler
+		server.Restarter = palworld.BackendRestarter{Client: palworldClient, Backend: scaler}
+	}
+
+	return bot, server
+}
+
+func enableSatisfactory(ctx context.Context, bot bot.Service) (bot.Service, game.Server) {
+	satisfactoryApproverRole := os.Getenv("SATISFACTORY_APPROVERS")
+	satisfactoryAPIURL := os.Getenv("SATISFACTORY_API_URL")
+	satisfactoryAPIToken := os.Getenv("SATISFACTORY_API_TOKEN")
+	satisfactoryAPICAFile := os.Getenv("SATISFACTORY_API_CA_FILE")
+	satisfactoryAPIInsecure := os.Getenv("SATISFACTORY_API_INSECURE")
+
+	satisfactoryClient := satisfactory.NewClient(satisfactoryAPIURL, satisfactoryAPIToken)
+	// the server generates a self-signed certificate unless one is provided
+	if len(satisfactoryAPICAFile) > 0 {
+		pem, err := os.ReadFile(satisfactoryAPICAFile)
+		if err != nil {
+			log.From(ctx).Fatal("reading satisfactory certificate", zap.Error(err))
+		}
+		satisfactoryClient, err = satisfactoryClient.WithCertificate(pem)
+		if err != nil {
+			log.From(ctx).Fatal("setting up satisfactory certificate", zap.Error(err))
+		}
+	} else if satisfactoryAPIInsecure == "true" {
+		satisfactoryClient = satisfactoryClient.WithInsecureSkipVerify()
+	}
+
+	if health, err := satisfactoryClient.HealthCheck(ctx); err != nil {
+		log.From(ctx).Error("checking satisfactory health", zap.Error(err))
+	} else {
+		log.From(ctx).Info("checked satisfactory health", zap.String("health", health))
+	}
+
+	// without a backend the server shuts down after saving and relies on its container being restarted
+	server := game.Server{
+		Name:         "satisfactory",
+		ApproverRole: satisfactoryApproverRole,
+		Client:       satisfactoryClient,
+		Restarter:    satisfactoryClient,
+		// the API doesn't report player names, so voters can't be verified
+		Vote:          setupRestartVote(ctx, "SATISFACTORY", nil),
+		ResourceAlert: setupResourceAlert(ctx, "SATISFACTORY"),
+		IdleWatcher:   setupIdleWatcher(ctx, "SATISFACTORY"),
+	}
+	if scaler := setupScaler(ctx, "SATISFACTORY"); scaler != nil {
+		server.Backend = scaler
+		server.Restarter = savingRestarter{saver: satisfactoryClient, restarter: scaler}
+	}
+
+	return bot, server
+}
+
+// savingRestarter saves the world before restarting the server
+type savingRestarter struct {
+	saver interface {
+		Save(ctx context.Context) error
+	}
+	restarter interface {
+		Restart(ctx context.Context) error
+	}
+}
+
+// Restart the server after saving, restarting anyway if saving fails as the server
+// might not respond anymore
+func (r savingRestarter) Restart(ctx context.Context) error {
+	if err := r.saver.Save(ctx); err != nil {
+		log.From(ctx).Error("saving before restart", zap.Error(err))
+	}
+	return r.restarter.Restart(ctx)
 }
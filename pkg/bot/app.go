@@ -34,7 +34,7 @@ func (s App) Session() *discordgo.Session {
 	return s.session
 }
 
-// Start the underlying session and wait for an Interrupt event to end it
+// Start the underlying session and wait for an Interrupt event or ctx to end it
 func (s App) Start(ctx context.Context) error {
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt)
@@ -42,7 +42,10 @@ func (s App) Start(ctx context.Context) error {
 	if err := s.session.Open(); err != nil {
 		return err
 	}
-	<-stop
+	select {
+	case <-stop:
+	case <-ctx.Done():
+	}
 	return nil
 }
 
@@ -9,6 +9,7 @@ import (
 type Service interface {
 	WithCommand(command ...Command) Service
 	WithOperand(operands ...Operand) Service
+	WithRunner(runners ...Runner) Service
 	Finalize(ctx context.Context, session *discordgo.Session) error
 }
 
@@ -32,3 +33,9 @@ type Command interface {
 	HandleCommand(ctx context.Context, session *discordgo.Session, i *discordgo.InteractionCreate) error
 	HandleInteractions(ctx context.Context, session *discordgo.Session, i *discordgo.InteractionCreate) error
 }
+
+// Runner defines the interface for a background task running alongside the bot
+type Runner interface {
+	Namer
+	Run(ctx context.Context, session *discordgo.Session) error
+}
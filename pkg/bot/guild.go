@@ -16,6 +16,7 @@ type Guild struct {
 
 	operands []Operand
 	commands []Command
+	runners  []Runner
 }
 
 // NewGuild returns a new Guild bot for the specified appID and guildID
@@ -38,12 +39,20 @@ func (b Guild) WithOperand(operands ...Operand) Service {
 	return b
 }
 
+// WithRunner returns a Guild with the Runner registered
+func (b Guild) WithRunner(runners ...Runner) Service {
+	b.runners = append(b.runners, runners...)
+	return b
+}
+
 // Finalize installs all registered commands and operands into the provided session
+// and starts the registered runners
 func (b Guild) Finalize(ctx context.Context, session *discordgo.Session) error {
 	b.session = session
 
 	b.installOperands(ctx)
 	b.installCommands(ctx)
+	startRunners(ctx, session, b.runners)
 
 	return nil
 }
@@ -67,6 +76,18 @@ func (b Guild) installCommands(ctx context.Context) {
 	}
 }
 
+func startRunners(ctx context.Context, session *discordgo.Session, runners []Runner) {
+	for _, runner := range runners {
+		ctx := log.WithFields(ctx, zap.String("name", runner.Name()))
+		log.From(ctx).Info("starting runner")
+		go func(runner Runner) {
+			if err := runner.Run(ctx, session); err != nil {
+				log.From(ctx).Error("running runner", zap.Error(err))
+			}
+		}(runner)
+	}
+}
+
 func loggingHandler(ctx context.Context, command Command) interface{} {
 	return func(session *discordgo.Session, i *discordgo.InteractionCreate) {
 		if err := handleMatching(ctx, command, session, i); err != nil {
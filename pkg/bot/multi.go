@@ -16,6 +16,7 @@ type Multi struct {
 
 	operands []Operand
 	commands []Command
+	runners  []Runner
 }
 
 // NewMulti returns a new Multi bot for the specified appID
@@ -37,7 +38,14 @@ func (b Multi) WithOperand(operands ...Operand) Service {
 	return b
 }
 
+// WithRunner returns a Multi with the Runner registered
+func (b Multi) WithRunner(runners ...Runner) Service {
+	b.runners = append(b.runners, runners...)
+	return b
+}
+
 // Finalize installs all registered commands and operands into the provided session
+// and starts the registered runners
 func (b Multi) Finalize(ctx context.Context, session *discordgo.Session) error {
 	b.session = session
 	l := sync.Mutex{}
@@ -63,5 +71,7 @@ func (b Multi) Finalize(ctx context.Context, session *discordgo.Session) error {
 		}
 	})
 
+	startRunners(ctx, session, b.runners)
+
 	return nil
 }
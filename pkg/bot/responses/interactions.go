@@ -2,10 +2,26 @@ package responses
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 )
 
+// MaxTimeout for editing and following up on an interaction response. Discord expires
+// interaction tokens after 15 minutes, which leaves a minute for the final response
+const MaxTimeout = 14 * time.Minute
+
+// Timeout for tracking an interaction, falling back to fallback if unset and capped to MaxTimeout
+func Timeout(timeout, fallback time.Duration) time.Duration {
+	if timeout <= 0 {
+		timeout = fallback
+	}
+	if timeout > MaxTimeout {
+		return MaxTimeout
+	}
+	return timeout
+}
+
 // NewInteractionError sends an ephemeral response containing the provided error
 func NewInteractionError(session *discordgo.Session, i *discordgo.InteractionCreate, err error) error {
 	return NewInteractionEphemeral(session, i, fmt.Sprintf("The bot encountered an error:\n%v", err))
@@ -23,3 +39,14 @@ func NewInteractionEphemeral(session *discordgo.Session, i *discordgo.Interactio
 		},
 	})
 }
+
+// NewInteractionEphemeralEmbed sends an ephemeral response containing embed only visible to the invoker
+func NewInteractionEphemeralEmbed(session *discordgo.Session, i *discordgo.InteractionCreate, embed *discordgo.MessageEmbed) error {
+	return session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+			Flags:  flagEphemeral,
+		},
+	})
+}
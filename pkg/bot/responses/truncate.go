@@ -0,0 +1,17 @@
+package responses
+
+import "unicode/utf8"
+
+// Truncate s to at most max bytes without splitting a multi-byte character, marking
+// truncated strings with an ellipsis. Discord limits are in characters, which a byte
+// limit never exceeds
+func Truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	cut := max - len("...")
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "..."
+}
@@ -0,0 +1,32 @@
+package responses
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name     string
+		s        string
+		max      int
+		expected string
+	}{
+		{name: "short", s: "Back-off restarting", max: 32, expected: "Back-off restarting"},
+		{name: "ascii", s: "Back-off restarting failed container", max: 16, expected: "Back-off rest..."},
+		// "ö" takes two bytes, cutting at 9 bytes for the ellipsis would split it
+		{name: "non-ascii", s: "Welt gelöscht, Neustart für Spieler", max: 12, expected: "Welt gel..."},
+		{name: "emoji", s: "🔥🔥🔥🔥", max: 10, expected: "🔥..."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			truncated := Truncate(tt.s, tt.max)
+			if truncated != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, truncated)
+			}
+			if len(truncated) > tt.max || !utf8.ValidString(truncated) {
+				t.Errorf("expected valid UTF-8 of at most %d bytes, got %q", tt.max, truncated)
+			}
+		})
+	}
+}
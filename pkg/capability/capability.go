@@ -0,0 +1,193 @@
+package capability
+
+import (
+	"context"
+	"time"
+)
+
+// Capability of a game client or backend
+type Capability string
+
+// Capabilities detected by the game registry
+const (
+	PlayerList      Capability = "player list"
+	PlayerDetails   Capability = "player details"
+	Messages        Capability = "messages"
+	Commands        Capability = "commands"
+	Whitelist       Capability = "whitelist"
+	Kick            Capability = "kick"
+	Restart         Capability = "restart"
+	RestartTracking Capability = "restart tracking"
+	Version         Capability = "version"
+	ServerInfo      Capability = "server info"
+	Rules           Capability = "rules"
+	Scale           Capability = "scale"
+	Progress        Capability = "progress"
+	Health          Capability = "health"
+	Usage           Capability = "usage"
+	Upgrade         Capability = "upgrade"
+	Logs            Capability = "logs"
+)
+
+// Declarer is implemented by clients and backends whose capabilities depend on their configuration,
+// e.g. a generic RCON client without a command to broadcast messages
+type Declarer interface {
+	Supports(capability Capability) bool
+}
+
+// PlayerCounter counts the players on a server, which all game clients are able to
+type PlayerCounter interface {
+	CountPlayers(ctx context.Context) (int, error)
+}
+
+// PlayerLister lists the names of the players on a server
+type PlayerLister interface {
+	Players(ctx context.Context) (int, []string, error)
+}
+
+// PlayerDetailLister lists the players on a server including details like their play time
+type PlayerDetailLister interface {
+	PlayerList(ctx context.Context) (int, []Player, error)
+}
+
+// MessageSender broadcasts messages to the players on a server
+type MessageSender interface {
+	SendMessage(ctx context.Context, msg string) error
+}
+
+// CommandSender runs console commands on a server
+type CommandSender interface {
+	SendCommand(ctx context.Context, command string) (Response, error)
+}
+
+// Whitelister allows players to join a server
+type Whitelister interface {
+	Whitelist(ctx context.Context, username string) error
+}
+
+// Kicker removes players from a server
+type Kicker interface {
+	Kick(ctx context.Context, player string) error
+}
+
+// Restarter restarts a server
+type Restarter interface {
+	Restart(ctx context.Context) error
+}
+
+// RestartTracker restarts a server and follows it until it's back
+type RestartTracker interface {
+	RestartTracked(ctx context.Context, progress func(phase Phase)) error
+}
+
+// Versioner reports the version of a server
+type Versioner interface {
+	Version(ctx context.Context) (string, error)
+}
+
+// Querier reports details about a server
+type Querier interface {
+	Details(ctx context.Context) (Details, error)
+}
+
+// RulesLister reports the rules of a server
+type RulesLister interface {
+	Rules(ctx context.Context) (map[string]string, error)
+}
+
+// Scaler starts and stops a server
+type Scaler interface {
+	ScaleUp(ctx context.Context) error
+	ScaleDown(ctx context.Context) error
+	Replicas(ctx context.Context) (int32, error)
+}
+
+// Backend restarts and scales a server, e.g. a Kubernetes workload
+type Backend interface {
+	Restarter
+	Scaler
+}
+
+// ProgressTracker follows a server coming up
+type ProgressTracker interface {
+	Phase(ctx context.Context) (Phase, error)
+	Events(ctx context.Context, limit int) ([]string, error)
+}
+
+// HealthSource reports the health of a server
+type HealthSource interface {
+	Health(ctx context.Context, warningLimit int) (ServerHealth, error)
+}
+
+// UsageSource reports the resource usage of a server
+type UsageSource interface {
+	Usage(ctx context.Context) (ResourceUsage, error)
+}
+
+// Upgrader changes the version of a server
+type Upgrader interface {
+	Spec(ctx context.Context) (ServerSpec, error)
+	Upgrade(ctx context.Context, version string) (ServerSpec, error)
+	Rollback(ctx context.Context, previous ServerSpec) error
+	RolledOut(ctx context.Context, since time.Time) (bool, error)
+	Replicas(ctx context.Context) (int32, error)
+	Events(ctx context.Context, limit int) ([]string, error)
+}
+
+// LogSource fetches the logs of a server
+type LogSource interface {
+	Logs(ctx context.Context, opts LogOptions) (string, string, error)
+}
+
+// Supports returns if impl has capability, i.e. it implements the matching interface
+// and doesn't declare otherwise
+func Supports(impl interface{}, capability Capability) bool {
+	if impl == nil || !implements(impl, capability) {
+		return false
+	}
+	if declarer, ok := impl.(Declarer); ok {
+		return declarer.Supports(capability)
+	}
+	return true
+}
+
+func implements(impl interface{}, capability Capability) bool {
+	var ok bool
+	switch capability {
+	case PlayerList:
+		_, ok = impl.(PlayerLister)
+	case PlayerDetails:
+		_, ok = impl.(PlayerDetailLister)
+	case Messages:
+		_, ok = impl.(MessageSender)
+	case Commands:
+		_, ok = impl.(CommandSender)
+	case Whitelist:
+		_, ok = impl.(Whitelister)
+	case Kick:
+		_, ok = impl.(Kicker)
+	case Restart:
+		_, ok = impl.(Restarter)
+	case RestartTracking:
+		_, ok = impl.(RestartTracker)
+	case Version:
+		_, ok = impl.(Versioner)
+	case ServerInfo:
+		_, ok = impl.(Querier)
+	case Rules:
+		_, ok = impl.(RulesLister)
+	case Scale:
+		_, ok = impl.(Scaler)
+	case Progress:
+		_, ok = impl.(ProgressTracker)
+	case Health:
+		_, ok = impl.(HealthSource)
+	case Usage:
+		_, ok = impl.(UsageSource)
+	case Upgrade:
+		_, ok = impl.(Upgrader)
+	case Logs:
+		_, ok = impl.(LogSource)
+	}
+	return ok
+}
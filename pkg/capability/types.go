@@ -0,0 +1,144 @@
+package capability
+
+import (
+	"fmt"
+	"time"
+)
+
+// Response of a console command
+type Response struct {
+	Body string
+}
+
+// Player on a server including details some servers provide
+type Player struct {
+	Name     string
+	Score    int
+	PlayTime time.Duration
+}
+
+// Details of the server for showing them to players
+type Details struct {
+	Name       string
+	Game       string
+	Map        string
+	Version    string
+	Players    int
+	MaxPlayers int
+	Password   bool
+	Ping       time.Duration
+}
+
+// Phase of a server coming up
+type Phase string
+
+const (
+	// PhaseTerminating indicates the previous pod is shutting down
+	PhaseTerminating Phase = "Terminating"
+	// PhaseScaling indicates no pod has been created yet
+	PhaseScaling Phase = "Scaling up"
+	// PhaseScheduling indicates the pod is waiting for a node
+	PhaseScheduling Phase = "Scheduling"
+	// PhaseCreating indicates the pod is scheduled and creating its containers, including pulling images
+	PhaseCreating Phase = "Creating containers"
+	// PhaseInitializing indicates init containers are running
+	PhaseInitializing Phase = "Initializing"
+	// PhaseStarting indicates the containers are running but not ready yet
+	PhaseStarting Phase = "Starting"
+	// PhaseReady indicates the pod is ready
+	PhaseReady Phase = "Ready"
+)
+
+// RestartFailure is returned if a tracked restart did not recover in time
+type RestartFailure struct {
+	// Phase the restart got stuck in
+	Phase Phase
+	// Events are the last warnings of the pod the restart got stuck on
+	Events []string
+	Err    error
+}
+
+func (f *RestartFailure) Error() string {
+	return fmt.Sprintf("restart stuck in phase %s: %s", f.Phase, f.Err)
+}
+
+func (f *RestartFailure) Unwrap() error {
+	return f.Err
+}
+
+// ServerHealth of a server and its newest pod
+type ServerHealth struct {
+	DesiredReplicas int32
+	ReadyReplicas   int32
+
+	// Pod fields are only set if the server has a pod
+	Pod             string
+	PodPhase        string
+	Restarts        int32
+	LastTermination string
+	Node            string
+	Created         time.Time
+	Warnings        []string
+}
+
+// ContainerUsage compares the current usage of a container with its requests and limits.
+// CPU is in millicores and memory in bytes, unset requests and limits are zero
+type ContainerUsage struct {
+	Name string
+
+	CPU        int64
+	CPURequest int64
+	CPULimit   int64
+
+	Memory        int64
+	MemoryRequest int64
+	MemoryLimit   int64
+}
+
+// ResourceUsage of a server pod
+type ResourceUsage struct {
+	Pod        string
+	Node       string
+	Containers []ContainerUsage
+}
+
+// MemoryLimitRatio returns the highest memory usage of a container relative to its limit,
+// as each container is killed once it exceeds its own limit. Containers without a memory
+// limit are skipped and false is returned if none has one
+func (u ResourceUsage) MemoryLimitRatio() (float64, bool) {
+	var highest float64
+	var limited bool
+	for _, container := range u.Containers {
+		if container.MemoryLimit < 1 {
+			continue
+		}
+		ratio := float64(container.Memory) / float64(container.MemoryLimit)
+		if !limited || ratio > highest {
+			highest = ratio
+		}
+		limited = true
+	}
+	return highest, limited
+}
+
+// ServerSpec describes the version of the server container
+type ServerSpec struct {
+	Container string
+	Image     string
+	// Version is the value of the version env or, if the container doesn't set it, the image tag
+	Version string
+	// VersionEnv is set if the container selects the version through it
+	VersionEnv string
+}
+
+// LogOptions for fetching the logs of a server
+type LogOptions struct {
+	// Lines from the end of the logs to fetch, all lines if unset
+	Lines int64
+	// Since limits the logs to the ones newer than the duration, if set
+	Since time.Duration
+	// Previous fetches the logs of the previously terminated container, e.g. after a crash
+	Previous bool
+	// Container to fetch the logs of, the first container of the pod if unset
+	Container string
+}
@@ -0,0 +1,48 @@
+package capability
+
+import "testing"
+
+const mebibyte = 1 << 20
+
+func TestResourceUsageMemoryLimitRatio(t *testing.T) {
+	tests := []struct {
+		name       string
+		containers []ContainerUsage
+		expected   float64
+		expectedOK bool
+	}{
+		{
+			name: "no containers",
+		},
+		{
+			name:       "unlimited",
+			containers: []ContainerUsage{{Name: "server", Memory: 512 * mebibyte}},
+		},
+		{
+			name: "limited and unlimited",
+			containers: []ContainerUsage{
+				{Name: "server", Memory: 900 * mebibyte, MemoryLimit: 1024 * mebibyte},
+				{Name: "backup", Memory: 2048 * mebibyte},
+			},
+			expected:   900.0 / 1024,
+			expectedOK: true,
+		},
+		{
+			name: "highest of limited",
+			containers: []ContainerUsage{
+				{Name: "server", Memory: 512 * mebibyte, MemoryLimit: 4096 * mebibyte},
+				{Name: "sidecar", Memory: 120 * mebibyte, MemoryLimit: 128 * mebibyte},
+			},
+			expected:   120.0 / 128,
+			expectedOK: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ratio, ok := ResourceUsage{Containers: tt.containers}.MemoryLimitRatio()
+			if ok != tt.expectedOK || ratio != tt.expected {
+				t.Errorf("expected %f %t, got %f %t", tt.expected, tt.expectedOK, ratio, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package evolution
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/playnet-public/mc-bot/pkg/bot/debounce"
+	"github.com/playnet-public/mc-bot/pkg/bot/extract"
+	"github.com/playnet-public/mc-bot/pkg/bot/responses"
+	"github.com/seibert-media/golibs/log"
+	"go.uber.org/zap"
+)
+
+const (
+	name      = "evolution"
+	refreshID = "refresh_evolution"
+)
+
+// Command for showing the evolution of the enemies on a Factorio server
+type Command struct {
+	Reporter interface {
+		Evolution(ctx context.Context) (string, error)
+	}
+}
+
+// Name of the Command
+func (c Command) Name() string {
+	return name
+}
+
+// Build the Command for installing
+func (c Command) Build() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        name,
+		Description: "Show the evolution factor of the biters",
+		Options:     []*discordgo.ApplicationCommandOption{},
+	}
+}
+
+// MatchInteraction returns if the Command can handle the interaction
+func (c Command) MatchInteraction(id string) bool {
+	return id == refreshID
+}
+
+// HandleCommand handles the initial event
+func (c Command) HandleCommand(ctx context.Context, session *discordgo.Session, i *discordgo.InteractionCreate) error {
+	return c.refreshEvolution(ctx, session, i, discordgo.InteractionResponseChannelMessageWithSource)
+}
+
+const debounceSeconds = 10
+
+// HandleInteractions handles follow-up interactions with the original message
+func (c Command) HandleInteractions(ctx context.Context, session *discordgo.Session, i *discordgo.InteractionCreate) error {
+	debouncer := debounce.InteractionTimestamp(extract.EmbedFieldValue(0, 0), debounceSeconds*time.Second)
+	if shouldDebounce, duration := debouncer(i); shouldDebounce {
+		return responses.NewInteractionEphemeral(session, i, fmt.Sprintf("Please wait at least %.f seconds before retrying.", duration.Seconds()))
+	}
+	return c.refreshEvolution(ctx, session, i, discordgo.InteractionResponseUpdateMessage)
+}
+
+func (c Command) refreshEvolution(ctx context.Context, session *discordgo.Session, i *discordgo.InteractionCreate, responseType discordgo.InteractionResponseType) error {
+	evolution, err := c.Reporter.Evolution(ctx)
+	if err != nil {
+		log.From(ctx).Error("getting evolution", zap.Error(err))
+		return responses.NewInteractionError(session, i, fmt.Errorf("failed getting evolution: %w", err))
+	}
+
+	return session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: responseType,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{
+				{
+					Title:       "Evolution",
+					Description: "Click Refresh to get the current evolution.",
+					Fields: []*discordgo.MessageEmbedField{
+						{
+							Name:  "Last Refresh",
+							Value: debounce.NewTimestampFor(time.Now()),
+						},
+						{
+							Name:  "Evolution",
+							Value: evolution,
+						},
+					},
+				},
+			},
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.Button{
+							Emoji: discordgo.ComponentEmoji{
+								Name: "♻️",
+							},
+							Label:    "Refresh",
+							Style:    discordgo.SecondaryButton,
+							CustomID: refreshID,
+						},
+					},
+				},
+			},
+		},
+	})
+}
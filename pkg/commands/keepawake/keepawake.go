@@ -0,0 +1,134 @@
+package keepawake
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/playnet-public/mc-bot/pkg/bot/responses"
+)
+
+const (
+	name = "keepawake"
+
+	defaultHours = 4
+)
+
+// Command for keeping a server awake while it's empty
+type Command struct {
+	ApproverRole string
+
+	Hold interface {
+		Set(until time.Time, by string)
+		Release()
+	}
+}
+
+// Name of the Command
+func (c Command) Name() string {
+	return name
+}
+
+// Build the Command for installing
+func (c Command) Build() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        name,
+		Description: "Keep the server awake even when it's empty",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "hours",
+				Description: fmt.Sprintf("How long to keep the server awake (default %d)", defaultHours),
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "release",
+				Description: "Release an active hold",
+				Required:    false,
+			},
+		},
+	}
+}
+
+// MatchInteraction returns if the Command can handle the interaction
+func (c Command) MatchInteraction(id string) bool {
+	return false
+}
+
+// HandleCommand handles the initial event
+func (c Command) HandleCommand(ctx context.Context, session *discordgo.Session, i *discordgo.InteractionCreate) error {
+	if !c.isApprover(i.Member) {
+		return responses.NewInteractionEphemeral(session, i, fmt.Sprintf("Only members with the <@&%s> role can keep the server awake.", c.ApproverRole))
+	}
+
+	hours := int64(defaultHours)
+	for _, option := range i.ApplicationCommandData().Options {
+		switch option.Name {
+		case "hours":
+			hours = option.IntValue()
+		case "release":
+			if option.BoolValue() {
+				return c.release(session, i)
+			}
+		}
+	}
+	if hours < 1 {
+		return responses.NewInteractionEphemeral(session, i, "The hold has to last at least one hour.")
+	}
+
+	until := time.Now().Add(time.Duration(hours) * time.Hour)
+	c.Hold.Set(until, i.Member.User.ID)
+
+	return session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{
+				{
+					Title:       "Keeping Server awake",
+					Description: fmt.Sprintf("%s is keeping the server awake. It won't be wound down automatically while the hold is active.", i.Member.Mention()),
+					Fields: []*discordgo.MessageEmbedField{
+						{
+							Name:  "Until",
+							Value: fmt.Sprintf("<t:%d:f>", until.Unix()),
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+// HandleInteractions handles follow-up interactions with the original message
+func (c Command) HandleInteractions(ctx context.Context, session *discordgo.Session, i *discordgo.InteractionCreate) error {
+	return nil
+}
+
+func (c Command) release(session *discordgo.Session, i *discordgo.InteractionCreate) error {
+	c.Hold.Release()
+
+	return session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{
+				{
+					Title:       "Released Hold",
+					Description: fmt.Sprintf("%s released the hold. The server will be wound down once it's idle.", i.Member.Mention()),
+				},
+			},
+		},
+	})
+}
+
+func (c Command) isApprover(member *discordgo.Member) bool {
+	if member == nil {
+		return false
+	}
+	for _, role := range member.Roles {
+		if role == c.ApproverRole {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package kick
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/playnet-public/mc-bot/pkg/bot/responses"
+	"github.com/playnet-public/mc-bot/pkg/capability"
+	"github.com/seibert-media/golibs/log"
+	"go.uber.org/zap"
+)
+
+const (
+	name = "kick"
+)
+
+// Command for kicking players from the server, which only approvers can use
+type Command struct {
+	ApproverRole string
+
+	Kicker capability.Kicker
+}
+
+// Name of the Command
+func (c Command) Name() string {
+	return name
+}
+
+// Build the Command for installing
+func (c Command) Build() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        name,
+		Description: "Kick a player from the server",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "player",
+				Description: "The name of the player as shown by /players",
+				Required:    true,
+			},
+		},
+	}
+}
+
+// MatchInteraction returns if the Command can handle the interaction
+func (c Command) MatchInteraction(id string) bool {
+	return false
+}
+
+// HandleCommand handles the initial event
+func (c Command) HandleCommand(ctx context.Context, session *discordgo.Session, i *discordgo.InteractionCreate) error {
+	if !c.isApprover(i.Member) {
+		return responses.NewInteractionEphemeral(session, i, fmt.Sprintf("Only members with the <@&%s> role can kick players.", c.ApproverRole))
+	}
+
+	if len(i.ApplicationCommandData().Options) < 1 {
+		return errors.New("invalid amount of options")
+	}
+	player := i.ApplicationCommandData().Options[0].StringValue()
+
+	if err := c.Kicker.Kick(ctx, player); err != nil {
+		log.From(ctx).Error("kicking player", zap.String("player", player), zap.Error(err))
+		return responses.NewInteractionError(session, i, fmt.Errorf("failed kicking %s: %w", player, err))
+	}
+
+	return session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{
+				{
+					Title:       "Kicked Player",
+					Description: fmt.Sprintf("%s kicked **%s** from the server.", i.Member.Mention(), player),
+				},
+			},
+		},
+	})
+}
+
+// HandleInteractions handles follow-up interactions with the original message
+func (c Command) HandleInteractions(ctx context.Context, session *discordgo.Session, i *discordgo.InteractionCreate) error {
+	return nil
+}
+
+func (c Command) isApprover(member *discordgo.Member) bool {
+	if member == nil {
+		return false
+	}
+	for _, role := range member.Roles {
+		if role == c.ApproverRole {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,415 @@
+package lists
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/playnet-public/mc-bot/pkg/bot/extract"
+	"github.com/playnet-public/mc-bot/pkg/bot/responses"
+	"github.com/playnet-public/mc-bot/pkg/commands/restart"
+	"github.com/playnet-public/mc-bot/pkg/valheim"
+	"github.com/seibert-media/golibs/log"
+	"go.uber.org/zap"
+)
+
+const (
+	name      = "valheim"
+	approveID = "approve_valheim_list"
+	denyID    = "deny_valheim_list"
+	restartID = "restart_valheim_list"
+
+	actionAdd    = "add"
+	actionRemove = "remove"
+	actionList   = "list"
+
+	// maxListLength leaves room for the code block in the 4096 characters of a description
+	maxListLength = 4000
+)
+
+// list managed through a subcommand group
+type list struct {
+	group string
+	file  valheim.List
+	title string
+}
+
+var lists = []list{
+	{group: "admin", file: valheim.AdminList, title: "Admin List"},
+	{group: "ban", file: valheim.BannedList, title: "Ban List"},
+	{group: "permit", file: valheim.PermittedList, title: "Permitted List"},
+}
+
+func steamIDOption(description string) []*discordgo.ApplicationCommandOption {
+	return []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "steam_id",
+			Description: description,
+			Required:    true,
+		},
+	}
+}
+
+func listFor(group string) (list, bool) {
+	for _, l := range lists {
+		if l.group == group {
+			return l, true
+		}
+	}
+	return list{}, false
+}
+
+// Store of a single list file
+type Store interface {
+	Load(ctx context.Context) (string, error)
+	Save(ctx context.Context, content string) error
+}
+
+// Command for managing the admin, ban and permitted lists of a Valheim server
+type Command struct {
+	ApproverRole string
+
+	// Stores of the lists, lists without a Store can't be managed
+	Stores map[valheim.List]Store
+
+	// Scheduler applies changes by restarting the server if requested, restarts are not offered if unset
+	Scheduler *restart.Scheduler
+}
+
+// Name of the Command
+func (c Command) Name() string {
+	return name
+}
+
+// Build the Command for installing
+func (c Command) Build() *discordgo.ApplicationCommand {
+	groups := []*discordgo.ApplicationCommandOption{}
+	for _, l := range lists {
+		if _, ok := c.Stores[l.file]; !ok {
+			continue
+		}
+		groups = append(groups, &discordgo.ApplicationCommandOption{
+			Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+			Name:        l.group,
+			Description: fmt.Sprintf("Manage the %s", strings.ToLower(l.title)),
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        actionAdd,
+					Description: fmt.Sprintf("Request adding a player to the %s", strings.ToLower(l.title)),
+					Options:     steamIDOption("The SteamID64 of the player, e.g. 76561197960287930"),
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        actionRemove,
+					Description: fmt.Sprintf("Request removing a player from the %s", strings.ToLower(l.title)),
+					Options:     steamIDOption("The SteamID64 of the player"),
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        actionList,
+					Description: fmt.Sprintf("Show the %s", strings.ToLower(l.title)),
+				},
+			},
+		})
+	}
+	return &discordgo.ApplicationCommand{
+		Name:        name,
+		Description: "Manage the admins, bans and permitted players of the Valheim server",
+		Options:     groups,
+	}
+}
+
+// MatchInteraction returns if the Command can handle the interaction
+func (c Command) MatchInteraction(id string) bool {
+	return id == approveID || id == denyID || id == restartID
+}
+
+// HandleCommand handles the initial event
+func (c Command) HandleCommand(ctx context.Context, session *discordgo.Session, i *discordgo.InteractionCreate) error {
+	if len(i.ApplicationCommandData().Options) < 1 {
+		return errors.New("invalid amount of options")
+	}
+	group := i.ApplicationCommandData().Options[0]
+	if len(group.Options) < 1 {
+		return errors.New("invalid amount of options")
+	}
+	subcommand := group.Options[0]
+
+	l, ok := listFor(group.Name)
+	if !ok {
+		return fmt.Errorf("unknown list %s", group.Name)
+	}
+	ids, err := c.load(ctx, l)
+	if err != nil {
+		return responses.NewInteractionError(session, i, err)
+	}
+
+	switch subcommand.Name {
+	case actionList:
+		return c.respondList(session, i, l, ids)
+	case actionAdd, actionRemove:
+		if len(subcommand.Options) < 1 {
+			return errors.New("invalid amount of options")
+		}
+		return c.respondRequest(session, i, l, ids, subcommand.Name, strings.TrimSpace(subcommand.Options[0].StringValue()))
+	}
+	return fmt.Errorf("unknown subcommand %s", subcommand.Name)
+}
+
+// HandleInteractions handles follow-up interactions with the original message
+func (c Command) HandleInteractions(ctx context.Context, session *discordgo.Session, i *discordgo.InteractionCreate) error {
+	if !c.isApprover(i.Member) {
+		return c.respondNotApprover(session, i)
+	}
+
+	if i.MessageComponentData().CustomID == restartID {
+		return c.respondRestart(ctx, session, i)
+	}
+
+	group, action, steamID, err := requestFrom(i.Message)
+	if err != nil {
+		return responses.NewInteractionError(session, i, fmt.Errorf("invalid list message: %w", err))
+	}
+	l, ok := listFor(group)
+	if !ok {
+		return responses.NewInteractionError(session, i, fmt.Errorf("unknown list %s", group))
+	}
+
+	if i.MessageComponentData().CustomID == denyID {
+		return session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseUpdateMessage,
+			Data: &discordgo.InteractionResponseData{
+				Components: []discordgo.MessageComponent{},
+				Embeds: []*discordgo.MessageEmbed{
+					{
+						Title:       fmt.Sprintf("%s change denied", l.title),
+						Description: fmt.Sprintf("The change of **%s** was denied.", steamID),
+					},
+				},
+			},
+		})
+	}
+
+	// the list is loaded again as it might have changed since the request
+	ids, err := c.load(ctx, l)
+	if err != nil {
+		return responses.NewInteractionError(session, i, err)
+	}
+	if err := validate(ids, action, steamID); err != nil {
+		return responses.NewInteractionEphemeral(session, i, err.Error())
+	}
+	if action == actionAdd {
+		ids = ids.Add(steamID)
+	} else {
+		ids = ids.Remove(steamID)
+	}
+	if err := c.Stores[l.file].Save(ctx, ids.String()); err != nil {
+		log.From(ctx).Error("saving valheim list", zap.String("list", string(l.file)), zap.Error(err))
+		return responses.NewInteractionError(session, i, fmt.Errorf("failed to save the %s: %w", strings.ToLower(l.title), err))
+	}
+	log.From(ctx).Info("changed valheim list", zap.String("list", string(l.file)), zap.String("action", action), zap.String("steamID", steamID))
+
+	components := []discordgo.MessageComponent{}
+	description := "Restart the server for the change to take effect."
+	if c.Scheduler != nil {
+		description = "Restart the server for the change to take effect, approvers can do so right away."
+		components = append(components, discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Emoji: discordgo.ComponentEmoji{
+						Name: "🔄",
+					},
+					Label:    "Restart",
+					Style:    discordgo.PrimaryButton,
+					CustomID: restartID,
+				},
+			},
+		})
+	}
+
+	return session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Components: components,
+			Embeds: []*discordgo.MessageEmbed{
+				{
+					Title:       fmt.Sprintf("%s changed", l.title),
+					Description: fmt.Sprintf("%s\n%s", diff(action, steamID), description),
+				},
+			},
+		},
+	})
+}
+
+func (c Command) respondRestart(ctx context.Context, session *discordgo.Session, i *discordgo.InteractionCreate) error {
+	if c.Scheduler == nil {
+		return responses.NewInteractionEphemeral(session, i, "Restarting the server is not supported.")
+	}
+	embed := &discordgo.MessageEmbed{Title: "List changed"}
+	if len(i.Message.Embeds) > 0 {
+		embed = i.Message.Embeds[0]
+	}
+	embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+		Name:  "Restart",
+		Value: c.Scheduler.Schedule(ctx),
+	})
+
+	return session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Components: []discordgo.MessageComponent{},
+			Embeds:     []*discordgo.MessageEmbed{embed},
+		},
+	})
+}
+
+func (c Command) load(ctx context.Context, l list) (valheim.IDList, error) {
+	store, ok := c.Stores[l.file]
+	if !ok {
+		return valheim.IDList{}, fmt.Errorf("the %s is not managed by the bot", strings.ToLower(l.title))
+	}
+	content, err := store.Load(ctx)
+	if err != nil {
+		log.From(ctx).Error("loading valheim list", zap.String("list", string(l.file)), zap.Error(err))
+		return valheim.IDList{}, fmt.Errorf("failed to load the %s: %w", strings.ToLower(l.title), err)
+	}
+	return valheim.ParseIDList(content), nil
+}
+
+// respondList only to approvers, as the SteamIDs identify the accounts of the players
+func (c Command) respondList(session *discordgo.Session, i *discordgo.InteractionCreate, l list, ids valheim.IDList) error {
+	if !c.isApprover(i.Member) {
+		return responses.NewInteractionEphemeral(session, i, fmt.Sprintf("Only members with the <@&%s> role can view the %s.", c.ApproverRole, strings.ToLower(l.title)))
+	}
+
+	content := strings.Builder{}
+	for _, id := range ids.IDs() {
+		line := id + "\n"
+		if content.Len()+len(line) > maxListLength {
+			content.WriteString("...\n")
+			break
+		}
+		content.WriteString(line)
+	}
+	description := fmt.Sprintf("```\n%s```", content.String())
+	if content.Len() < 1 {
+		description = "The list is empty."
+	}
+	return responses.NewInteractionEphemeralEmbed(session, i, &discordgo.MessageEmbed{
+		Title:       l.title,
+		Description: description,
+	})
+}
+
+func (c Command) respondRequest(session *discordgo.Session, i *discordgo.InteractionCreate, l list, ids valheim.IDList, action, steamID string) error {
+	if err := validate(ids, action, steamID); err != nil {
+		return responses.NewInteractionEphemeral(session, i, err.Error())
+	}
+
+	description := fmt.Sprintf("%s\nPlease wait for approval :-)", diff(action, steamID))
+	if l.file == valheim.PermittedList && action == actionAdd && len(ids.IDs()) < 1 {
+		description = fmt.Sprintf("%s\nOnly permitted players can join once the list isn't empty.\nPlease wait for approval :-)", diff(action, steamID))
+	}
+
+	return session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{
+				{
+					Title:       fmt.Sprintf("Requesting %s Change", l.title),
+					Description: description,
+					Fields: []*discordgo.MessageEmbedField{
+						{Name: "List", Value: l.group, Inline: true},
+						{Name: "Action", Value: action, Inline: true},
+						{Name: "Steam ID", Value: steamID, Inline: true},
+					},
+				},
+			},
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.Button{
+							Emoji: discordgo.ComponentEmoji{
+								Name: "✅",
+							},
+							Label:    "Approve",
+							Style:    discordgo.SuccessButton,
+							CustomID: approveID,
+						},
+						discordgo.Button{
+							Emoji: discordgo.ComponentEmoji{
+								Name: "✖️",
+							},
+							Label:    "Deny",
+							Style:    discordgo.DangerButton,
+							CustomID: denyID,
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+// requestFrom extracts the requested change from a request message
+func requestFrom(m *discordgo.Message) (string, string, string, error) {
+	group, err := extract.EmbedFieldValue(0, 0)(m)
+	if err != nil {
+		return "", "", "", err
+	}
+	action, err := extract.EmbedFieldValue(0, 1)(m)
+	if err != nil {
+		return "", "", "", err
+	}
+	steamID, err := extract.EmbedFieldValue(0, 2)(m)
+	if err != nil {
+		return "", "", "", err
+	}
+	return group, action, steamID, nil
+}
+
+func validate(ids valheim.IDList, action, steamID string) error {
+	if err := valheim.ValidateSteamID(steamID); err != nil {
+		return err
+	}
+	switch action {
+	case actionAdd:
+		if ids.Contains(steamID) {
+			return fmt.Errorf("%s is already on the list", steamID)
+		}
+	case actionRemove:
+		if !ids.Contains(steamID) {
+			return fmt.Errorf("%s is not on the list", steamID)
+		}
+	default:
+		return fmt.Errorf("unknown action %s", action)
+	}
+	return nil
+}
+
+func diff(action, steamID string) string {
+	if action == actionRemove {
+		return fmt.Sprintf("```diff\n- %s\n```", steamID)
+	}
+	return fmt.Sprintf("```diff\n+ %s\n```", steamID)
+}
+
+func (c Command) isApprover(member *discordgo.Member) bool {
+	if member == nil {
+		return false
+	}
+	for _, role := range member.Roles {
+		if role == c.ApproverRole {
+			return true
+		}
+	}
+	return false
+}
+
+func (c Command) respondNotApprover(session *discordgo.Session, i *discordgo.InteractionCreate) error {
+	return responses.NewInteractionEphemeral(session, i, fmt.Sprintf("Only members with the <@&%s> role can approve list changes. Please wait :-)", c.ApproverRole))
+}
@@ -0,0 +1,449 @@
+package logs
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/playnet-public/mc-bot/pkg/bot/extract"
+	"github.com/playnet-public/mc-bot/pkg/bot/responses"
+	"github.com/playnet-public/mc-bot/pkg/capability"
+	"github.com/seibert-media/golibs/log"
+	"go.uber.org/zap"
+)
+
+const (
+	name    = "logs"
+	olderID = "older_logs"
+	newerID = "newer_logs"
+
+	defaultLines = 100
+	maxLines     = 5000
+	// pageSize leaves room for the code block in the 2000 characters of a message
+	pageSize = 1900
+	none     = "<none>"
+
+	// snapshotLifetime is the time the logs of a message are kept for paging
+	snapshotLifetime = 1 * time.Hour
+)
+
+// Command for viewing the logs of the server
+type Command struct {
+	ApproverRole string
+
+	Logger capability.LogSource
+
+	// Snapshots keep the logs shown in each message, so paging doesn't change them.
+	// The logs are fetched again on every page if unset
+	Snapshots *Snapshots
+}
+
+// Snapshots of the logs shown in messages, keyed by message ID
+type Snapshots struct {
+	l         sync.Mutex
+	snapshots map[string]snapshot
+}
+
+type snapshot struct {
+	pod   string
+	pages []string
+}
+
+// store the snapshot of messageID until it expires after snapshotLifetime
+func (s *Snapshots) store(messageID string, snap snapshot) {
+	s.l.Lock()
+	defer s.l.Unlock()
+	if s.snapshots == nil {
+		s.snapshots = make(map[string]snapshot)
+	}
+	s.snapshots[messageID] = snap
+	time.AfterFunc(snapshotLifetime, func() {
+		s.l.Lock()
+		defer s.l.Unlock()
+		delete(s.snapshots, messageID)
+	})
+}
+
+func (s *Snapshots) load(messageID string) (snapshot, bool) {
+	s.l.Lock()
+	defer s.l.Unlock()
+	snap, ok := s.snapshots[messageID]
+	return snap, ok
+}
+
+// Name of the Command
+func (c Command) Name() string {
+	return name
+}
+
+// Build the Command for installing
+func (c Command) Build() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        name,
+		Description: "Show the logs of the server",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "lines",
+				Description: fmt.Sprintf("Number of lines from the end of the logs (default %d)", defaultLines),
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "since",
+				Description: "Only show logs newer than this duration, e.g. 15m",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "grep",
+				Description: "Only show lines matching this regular expression",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "container",
+				Description: "The container to show the logs of (default: the server container)",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "previous",
+				Description: "Show the logs of the previous container, e.g. after a crash",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "attachment",
+				Description: "Send the logs as text file instead of paginated messages",
+				Required:    false,
+			},
+		},
+	}
+}
+
+// MatchInteraction returns if the Command can handle the interaction
+func (c Command) MatchInteraction(id string) bool {
+	return id == olderID || id == newerID
+}
+
+// query describes the requested logs and is stored in the response embed
+type query struct {
+	lines     int64
+	since     time.Duration
+	grep      string
+	previous  bool
+	container string
+}
+
+func (q query) options() capability.LogOptions {
+	return capability.LogOptions{
+		Lines:     q.lines,
+		Since:     q.since,
+		Previous:  q.previous,
+		Container: q.container,
+	}
+}
+
+// HandleCommand handles the initial event
+func (c Command) HandleCommand(ctx context.Context, session *discordgo.Session, i *discordgo.InteractionCreate) error {
+	if !c.isApprover(i.Member) {
+		return c.respondNotApprover(session, i)
+	}
+
+	q := query{lines: defaultLines}
+	attachment := false
+	for _, option := range i.ApplicationCommandData().Options {
+		switch option.Name {
+		case "lines":
+			q.lines = option.IntValue()
+		case "since":
+			since, err := time.ParseDuration(option.StringValue())
+			if err != nil {
+				return responses.NewInteractionEphemeral(session, i, fmt.Sprintf("Invalid duration %q, try something like 15m or 2h.", option.StringValue()))
+			}
+			q.since = since
+		case "grep":
+			q.grep = option.StringValue()
+		case "previous":
+			q.previous = option.BoolValue()
+		case "container":
+			q.container = option.StringValue()
+		case "attachment":
+			attachment = option.BoolValue()
+		}
+	}
+	if q.lines < 1 || q.lines > maxLines {
+		return responses.NewInteractionEphemeral(session, i, fmt.Sprintf("Lines have to be between 1 and %d.", maxLines))
+	}
+
+	pod, lines, err := c.fetch(ctx, q)
+	if err != nil {
+		return responses.NewInteractionError(session, i, err)
+	}
+
+	if attachment {
+		return c.respondAttachment(session, i, pod, q, lines)
+	}
+
+	pages := paginate(lines)
+	if err := c.respondPage(session, i, discordgo.InteractionResponseChannelMessageWithSource, pod, q, pages, len(pages)-1); err != nil {
+		return err
+	}
+	if c.Snapshots == nil {
+		return nil
+	}
+	msg, err := session.InteractionResponse(session.State.User.ID, i.Interaction)
+	if err != nil {
+		log.From(ctx).Error("getting logs message", zap.Error(err))
+		return nil
+	}
+	c.Snapshots.store(msg.ID, snapshot{pod: pod, pages: pages})
+	return nil
+}
+
+// HandleInteractions handles follow-up interactions with the original message
+func (c Command) HandleInteractions(ctx context.Context, session *discordgo.Session, i *discordgo.InteractionCreate) error {
+	if !c.isApprover(i.Member) {
+		return c.respondNotApprover(session, i)
+	}
+
+	q, page, err := queryFrom(i.Message)
+	if err != nil {
+		return responses.NewInteractionError(session, i, fmt.Errorf("invalid logs message: %w", err))
+	}
+
+	switch i.MessageComponentData().CustomID {
+	case olderID:
+		page--
+	case newerID:
+		page++
+	}
+
+	pod, pages, err := c.pages(ctx, i.Message.ID, q)
+	if err != nil {
+		return responses.NewInteractionError(session, i, err)
+	}
+	if page < 0 {
+		page = 0
+	}
+	if page > len(pages)-1 {
+		page = len(pages) - 1
+	}
+
+	return c.respondPage(session, i, discordgo.InteractionResponseUpdateMessage, pod, q, pages, page)
+}
+
+// pages of the logs shown in messageID, which are fetched again if there's no snapshot of them
+func (c Command) pages(ctx context.Context, messageID string, q query) (string, []string, error) {
+	if c.Snapshots != nil {
+		if snap, ok := c.Snapshots.load(messageID); ok {
+			return snap.pod, snap.pages, nil
+		}
+	}
+	pod, lines, err := c.fetch(ctx, q)
+	if err != nil {
+		return "", nil, err
+	}
+	return pod, paginate(lines), nil
+}
+
+// fetch the logs for q, returning the pod name and the matching lines
+func (c Command) fetch(ctx context.Context, q query) (string, []string, error) {
+	var filter *regexp.Regexp
+	if len(q.grep) > 0 {
+		var err error
+		filter, err = regexp.Compile(q.grep)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid filter %q: %w", q.grep, err)
+		}
+	}
+
+	pod, logs, err := c.Logger.Logs(ctx, q.options())
+	if err != nil {
+		log.From(ctx).Error("fetching logs", zap.Error(err))
+		return "", nil, fmt.Errorf("failed to fetch logs: %w", err)
+	}
+
+	var lines []string
+	for _, line := range strings.Split(strings.TrimRight(logs, "\n"), "\n") {
+		if filter != nil && !filter.MatchString(line) {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return pod, lines, nil
+}
+
+// paginate lines into pages fitting into a message, always returning at least one page
+func paginate(lines []string) []string {
+	pages := []string{}
+	current := strings.Builder{}
+	for _, line := range lines {
+		// code blocks can't be escaped, so they are replaced
+		line = strings.ReplaceAll(line, "```", "'''")
+		line = responses.Truncate(line, pageSize)
+		if current.Len()+len(line)+1 > pageSize {
+			pages = append(pages, current.String())
+			current.Reset()
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+	}
+	if current.Len() > 0 || len(pages) < 1 {
+		pages = append(pages, current.String())
+	}
+	return pages
+}
+
+func (c Command) respondPage(session *discordgo.Session, i *discordgo.InteractionCreate, responseType discordgo.InteractionResponseType, pod string, q query, pages []string, page int) error {
+	content := pages[page]
+	if len(strings.TrimSpace(content)) < 1 {
+		content = "no matching logs"
+	}
+
+	return session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: responseType,
+		Data: &discordgo.InteractionResponseData{
+			Content: fmt.Sprintf("```\n%s```", content),
+			Embeds:  []*discordgo.MessageEmbed{queryEmbed(pod, q, fmt.Sprintf("%d/%d", page+1, len(pages)))},
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.Button{
+							Emoji: discordgo.ComponentEmoji{
+								Name: "◀️",
+							},
+							Label:    "Older",
+							Style:    discordgo.SecondaryButton,
+							CustomID: olderID,
+							Disabled: page < 1,
+						},
+						discordgo.Button{
+							Emoji: discordgo.ComponentEmoji{
+								Name: "▶️",
+							},
+							Label:    "Newer",
+							Style:    discordgo.SecondaryButton,
+							CustomID: newerID,
+							Disabled: page >= len(pages)-1,
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+func (c Command) respondAttachment(session *discordgo.Session, i *discordgo.InteractionCreate, pod string, q query, lines []string) error {
+	return session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{queryEmbed(pod, q, "attached")},
+			Files: []*discordgo.File{
+				{
+					Name:        pod + ".log",
+					ContentType: "text/plain",
+					Reader:      strings.NewReader(strings.Join(lines, "\n")),
+				},
+			},
+		},
+	})
+}
+
+const (
+	linesFieldIndex = iota + 1
+	sinceFieldIndex
+	grepFieldIndex
+	previousFieldIndex
+	pageFieldIndex
+	containerFieldIndex
+)
+
+func queryEmbed(pod string, q query, page string) *discordgo.MessageEmbed {
+	since := none
+	if q.since > 0 {
+		since = q.since.String()
+	}
+	grep := none
+	if len(q.grep) > 0 {
+		grep = q.grep
+	}
+	container := none
+	if len(q.container) > 0 {
+		container = q.container
+	}
+
+	return &discordgo.MessageEmbed{
+		Title: "Server Logs",
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Pod", Value: pod, Inline: true},
+			{Name: "Lines", Value: strconv.FormatInt(q.lines, 10), Inline: true},
+			{Name: "Since", Value: since, Inline: true},
+			{Name: "Filter", Value: grep, Inline: true},
+			{Name: "Previous", Value: strconv.FormatBool(q.previous), Inline: true},
+			{Name: "Page", Value: page, Inline: true},
+			{Name: "Container", Value: container, Inline: true},
+		},
+	}
+}
+
+// queryFrom extracts the query and zero based page from a logs message
+func queryFrom(m *discordgo.Message) (query, int, error) {
+	values := make(map[int]string)
+	for _, index := range []int{linesFieldIndex, sinceFieldIndex, grepFieldIndex, previousFieldIndex, pageFieldIndex} {
+		value, err := extract.EmbedFieldValue(0, index)(m)
+		if err != nil {
+			return query{}, 0, err
+		}
+		values[index] = value
+	}
+
+	q := query{}
+	var err error
+	if q.lines, err = strconv.ParseInt(values[linesFieldIndex], 10, 64); err != nil {
+		return query{}, 0, err
+	}
+	if values[sinceFieldIndex] != none {
+		if q.since, err = time.ParseDuration(values[sinceFieldIndex]); err != nil {
+			return query{}, 0, err
+		}
+	}
+	if values[grepFieldIndex] != none {
+		q.grep = values[grepFieldIndex]
+	}
+	if q.previous, err = strconv.ParseBool(values[previousFieldIndex]); err != nil {
+		return query{}, 0, err
+	}
+	// messages sent before containers could be selected don't have the field
+	if container, err := extract.EmbedFieldValue(0, containerFieldIndex)(m); err == nil && container != none {
+		q.container = container
+	}
+
+	page, err := strconv.Atoi(strings.SplitN(values[pageFieldIndex], "/", 2)[0])
+	if err != nil {
+		return query{}, 0, err
+	}
+	return q, page - 1, nil
+}
+
+func (c Command) isApprover(member *discordgo.Member) bool {
+	if member == nil {
+		return false
+	}
+	for _, role := range member.Roles {
+		if role == c.ApproverRole {
+			return true
+		}
+	}
+	return false
+}
+
+func (c Command) respondNotApprover(session *discordgo.Session, i *discordgo.InteractionCreate) error {
+	return responses.NewInteractionEphemeral(session, i, fmt.Sprintf("Only members with the <@&%s> role can view the logs.", c.ApproverRole))
+}
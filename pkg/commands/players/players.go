@@ -11,18 +11,22 @@ import (
 	"github.com/playnet-public/mc-bot/pkg/bot/debounce"
 	"github.com/playnet-public/mc-bot/pkg/bot/extract"
 	"github.com/playnet-public/mc-bot/pkg/bot/responses"
+	"github.com/playnet-public/mc-bot/pkg/capability"
 )
 
 const (
 	name      = "players"
 	refreshID = "refresh_players"
+
+	// unknownPlayer is listed for players without a name
+	unknownPlayer = "<unknown>"
 )
 
 // Command for listing users on a server
 type Command struct {
-	PlayerLister interface {
-		Players(ctx context.Context) (int, []string, error)
-	}
+	PlayerLister capability.PlayerLister
+	// Details lists the players with their play time instead of PlayerLister if set
+	Details      capability.PlayerDetailLister
 	PollInterval time.Duration
 }
 
@@ -62,7 +66,7 @@ func (c Command) HandleInteractions(ctx context.Context, session *discordgo.Sess
 }
 
 func (c Command) refreshPlayers(ctx context.Context, session *discordgo.Session, i *discordgo.InteractionCreate, responseType discordgo.InteractionResponseType) error {
-	playerCount, players, err := c.PlayerLister.Players(ctx)
+	playerCount, players, err := c.players(ctx)
 	if err != nil {
 		return responses.NewInteractionError(session, i, fmt.Errorf("failed getting player count: %w", err))
 	}
@@ -112,3 +116,30 @@ func (c Command) refreshPlayers(ctx context.Context, session *discordgo.Session,
 		},
 	})
 }
+
+// players returns the number of players and their names, including their play time if Details is set
+func (c Command) players(ctx context.Context) (int, []string, error) {
+	if c.Details == nil {
+		return c.PlayerLister.Players(ctx)
+	}
+
+	playerCount, details, err := c.Details.PlayerList(ctx)
+	if err != nil {
+		return -1, nil, err
+	}
+	players := make([]string, 0, len(details))
+	for _, player := range details {
+		playerName := player.Name
+		if len(playerName) < 1 {
+			playerName = unknownPlayer
+		}
+		if player.PlayTime > 0 {
+			playerName = fmt.Sprintf("%s (%s)", playerName, player.PlayTime.Round(time.Minute))
+		}
+		players = append(players, playerName)
+	}
+	if playerCount > 0 && len(players) < 1 {
+		players = append(players, unknownPlayer)
+	}
+	return playerCount, players, nil
+}
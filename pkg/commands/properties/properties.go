@@ -0,0 +1,363 @@
+package properties
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/playnet-public/mc-bot/pkg/bot/extract"
+	"github.com/playnet-public/mc-bot/pkg/bot/responses"
+	"github.com/playnet-public/mc-bot/pkg/commands/restart"
+	"github.com/playnet-public/mc-bot/pkg/minecraft"
+	"github.com/seibert-media/golibs/log"
+	"go.uber.org/zap"
+)
+
+const (
+	name      = "properties"
+	approveID = "approve_properties"
+	denyID    = "deny_properties"
+
+	// maxListLength leaves room for the code block in the 4096 characters of a description
+	maxListLength = 4000
+	unset         = "<unset>"
+	empty         = "<empty>"
+)
+
+// Command for reading and editing the server.properties of a Minecraft server
+type Command struct {
+	ApproverRole string
+
+	Store interface {
+		Load(ctx context.Context) (string, error)
+		Save(ctx context.Context, content string) error
+	}
+
+	// Scheduler applies changes by restarting the server if requested, restarts can't be requested if unset
+	Scheduler *restart.Scheduler
+}
+
+// Name of the Command
+func (c Command) Name() string {
+	return name
+}
+
+// Build the Command for installing
+func (c Command) Build() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        name,
+		Description: "Read or change the server.properties",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "get",
+				Description: "Show the server properties",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "key",
+						Description: "The property to show, all if unset",
+						Required:    false,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "set",
+				Description: "Request a change of a server property",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "key",
+						Description: "The property to change, e.g. difficulty",
+						Required:    true,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "value",
+						Description: "The new value",
+						Required:    true,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionBoolean,
+						Name:        "restart",
+						Description: "Restart the server so the change takes effect",
+						Required:    false,
+					},
+				},
+			},
+		},
+	}
+}
+
+// MatchInteraction returns if the Command can handle the interaction
+func (c Command) MatchInteraction(id string) bool {
+	return id == approveID || id == denyID
+}
+
+// HandleCommand handles the initial event
+func (c Command) HandleCommand(ctx context.Context, session *discordgo.Session, i *discordgo.InteractionCreate) error {
+	if len(i.ApplicationCommandData().Options) < 1 {
+		return errors.New("invalid amount of options")
+	}
+	subcommand := i.ApplicationCommandData().Options[0]
+	options := make(map[string]*discordgo.ApplicationCommandInteractionDataOption)
+	for _, option := range subcommand.Options {
+		options[option.Name] = option
+	}
+
+	properties, err := c.load(ctx)
+	if err != nil {
+		return responses.NewInteractionError(session, i, err)
+	}
+
+	switch subcommand.Name {
+	case "get":
+		if key, ok := options["key"]; ok {
+			return c.respondProperty(session, i, properties, strings.TrimSpace(key.StringValue()))
+		}
+		return c.respondList(session, i, properties)
+	case "set":
+		restart := false
+		if option, ok := options["restart"]; ok {
+			restart = option.BoolValue()
+		}
+		return c.respondRequest(session, i, properties, strings.TrimSpace(options["key"].StringValue()), strings.TrimSpace(options["value"].StringValue()), restart)
+	}
+	return fmt.Errorf("unknown subcommand %s", subcommand.Name)
+}
+
+// HandleInteractions handles follow-up interactions with the original message
+func (c Command) HandleInteractions(ctx context.Context, session *discordgo.Session, i *discordgo.InteractionCreate) error {
+	if !c.isApprover(i.Member) {
+		return c.respondNotApprover(session, i)
+	}
+
+	key, value, restart, err := requestFrom(i.Message)
+	if err != nil {
+		return responses.NewInteractionError(session, i, fmt.Errorf("invalid properties message: %w", err))
+	}
+
+	if i.MessageComponentData().CustomID == denyID {
+		return session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseUpdateMessage,
+			Data: &discordgo.InteractionResponseData{
+				Components: []discordgo.MessageComponent{},
+				Embeds: []*discordgo.MessageEmbed{
+					{
+						Title:       "Property change denied",
+						Description: fmt.Sprintf("The change of **%s** was denied.", key),
+					},
+				},
+			},
+		})
+	}
+
+	// the properties are loaded again as they might have changed since the request
+	properties, err := c.load(ctx)
+	if err != nil {
+		return responses.NewInteractionError(session, i, err)
+	}
+	if err := validate(properties, key, value); err != nil {
+		return responses.NewInteractionEphemeral(session, i, err.Error())
+	}
+	previous, _ := properties.Get(key)
+	if err := c.Store.Save(ctx, properties.Set(key, value).String()); err != nil {
+		log.From(ctx).Error("saving server properties", zap.Error(err))
+		return responses.NewInteractionError(session, i, fmt.Errorf("failed to save the server properties: %w", err))
+	}
+	log.From(ctx).Info("changed server property", zap.String("key", key), zap.String("value", value))
+
+	description := "Restart the server for the change to take effect."
+	if restart && c.Scheduler != nil {
+		description = c.Scheduler.Schedule(ctx)
+	}
+
+	return session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Components: []discordgo.MessageComponent{},
+			Embeds: []*discordgo.MessageEmbed{
+				{
+					Title:       "Property changed",
+					Description: fmt.Sprintf("%s\n%s", diff(key, previous, value), description),
+				},
+			},
+		},
+	})
+}
+
+func (c Command) load(ctx context.Context) (minecraft.Properties, error) {
+	content, err := c.Store.Load(ctx)
+	if err != nil {
+		log.From(ctx).Error("loading server properties", zap.Error(err))
+		return minecraft.Properties{}, fmt.Errorf("failed to load the server properties: %w", err)
+	}
+	return minecraft.ParseProperties(content), nil
+}
+
+func (c Command) respondProperty(session *discordgo.Session, i *discordgo.InteractionCreate, properties minecraft.Properties, key string) error {
+	value, ok := properties.Get(key)
+	if !ok {
+		value = unset
+	} else if len(value) < 1 {
+		value = empty
+	}
+	if isSecret(key) {
+		value = redacted
+	}
+	return session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{
+				{
+					Title: "Server Properties",
+					Fields: []*discordgo.MessageEmbedField{
+						{
+							Name:  key,
+							Value: value,
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+func (c Command) respondList(session *discordgo.Session, i *discordgo.InteractionCreate, properties minecraft.Properties) error {
+	list := strings.Builder{}
+	for _, key := range properties.Keys() {
+		value, _ := properties.Get(key)
+		if isSecret(key) {
+			value = redacted
+		}
+		line := fmt.Sprintf("%s=%s\n", key, value)
+		if list.Len()+len(line) > maxListLength {
+			list.WriteString("...\n")
+			break
+		}
+		list.WriteString(line)
+	}
+	return session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{
+				{
+					Title:       "Server Properties",
+					Description: fmt.Sprintf("```properties\n%s```", strings.ReplaceAll(list.String(), "```", "'''")),
+				},
+			},
+		},
+	})
+}
+
+func (c Command) respondRequest(session *discordgo.Session, i *discordgo.InteractionCreate, properties minecraft.Properties, key, value string, restart bool) error {
+	if err := validate(properties, key, value); err != nil {
+		return responses.NewInteractionEphemeral(session, i, err.Error())
+	}
+	previous, _ := properties.Get(key)
+	if previous == value {
+		return responses.NewInteractionEphemeral(session, i, fmt.Sprintf("%s is already set to %s.", key, value))
+	}
+	if restart && c.Scheduler == nil {
+		return responses.NewInteractionEphemeral(session, i, "Restarting the server is not supported, please request the change without restart.")
+	}
+
+	return session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{
+				{
+					Title:       "Requesting Property Change",
+					Description: fmt.Sprintf("%s\nPlease wait for approval :-)", diff(key, previous, value)),
+					Fields: []*discordgo.MessageEmbedField{
+						{Name: "Key", Value: key, Inline: true},
+						{Name: "Value", Value: value, Inline: true},
+						{Name: "Restart", Value: strconv.FormatBool(restart), Inline: true},
+					},
+				},
+			},
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.Button{
+							Emoji: discordgo.ComponentEmoji{
+								Name: "✅",
+							},
+							Label:    "Approve",
+							Style:    discordgo.SuccessButton,
+							CustomID: approveID,
+						},
+						discordgo.Button{
+							Emoji: discordgo.ComponentEmoji{
+								Name: "✖️",
+							},
+							Label:    "Deny",
+							Style:    discordgo.DangerButton,
+							CustomID: denyID,
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+// requestFrom extracts the requested change from a request message
+func requestFrom(m *discordgo.Message) (string, string, bool, error) {
+	key, err := extract.EmbedFieldValue(0, 0)(m)
+	if err != nil {
+		return "", "", false, err
+	}
+	value, err := extract.EmbedFieldValue(0, 1)(m)
+	if err != nil {
+		return "", "", false, err
+	}
+	restartValue, err := extract.EmbedFieldValue(0, 2)(m)
+	if err != nil {
+		return "", "", false, err
+	}
+	restart, err := strconv.ParseBool(restartValue)
+	if err != nil {
+		return "", "", false, err
+	}
+	return key, value, restart, nil
+}
+
+const redacted = "<redacted>"
+
+// isSecret returns if the property must not be shown or changed through Discord
+func isSecret(key string) bool {
+	key = strings.ToLower(key)
+	return strings.Contains(key, "password") || strings.Contains(key, "secret")
+}
+
+func validate(properties minecraft.Properties, key, value string) error {
+	if isSecret(key) {
+		return fmt.Errorf("%s can't be changed through Discord", key)
+	}
+	return minecraft.ValidateProperty(properties, key, value)
+}
+
+func diff(key, previous, value string) string {
+	return fmt.Sprintf("```diff\n- %s=%s\n+ %s=%s\n```", key, previous, key, value)
+}
+
+func (c Command) isApprover(member *discordgo.Member) bool {
+	if member == nil {
+		return false
+	}
+	for _, role := range member.Roles {
+		if role == c.ApproverRole {
+			return true
+		}
+	}
+	return false
+}
+
+func (c Command) respondNotApprover(session *discordgo.Session, i *discordgo.InteractionCreate) error {
+	return responses.NewInteractionEphemeral(session, i, fmt.Sprintf("Only members with the <@&%s> role can approve property changes. Please wait :-)", c.ApproverRole))
+}
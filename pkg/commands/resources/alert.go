@@ -0,0 +1,80 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/playnet-public/mc-bot/pkg/capability"
+	"github.com/seibert-media/golibs/log"
+	"go.uber.org/zap"
+)
+
+const alertName = "resource-alert"
+
+// Alert posts to a channel when the memory usage of the server approaches its limit
+type Alert struct {
+	// ChannelID to post alerts in
+	ChannelID string
+	// Interval between usage samples
+	Interval time.Duration
+	// Threshold of the memory limit, e.g. 0.9, above which an alert is posted.
+	// Another alert is only posted after the usage dropped below the threshold again
+	Threshold float64
+
+	Usage capability.UsageSource
+}
+
+// Name of the Runner
+func (a Alert) Name() string {
+	return alertName
+}
+
+// Run the alert until ctx is done
+func (a Alert) Run(ctx context.Context, session *discordgo.Session) error {
+	ticker := time.NewTicker(a.Interval)
+	defer ticker.Stop()
+
+	alerted := false
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			alerted = a.sample(ctx, session, alerted)
+		}
+	}
+}
+
+// sample the memory usage and return whether the usage is alerted
+func (a Alert) sample(ctx context.Context, session *discordgo.Session, alerted bool) bool {
+	usage, err := a.Usage.Usage(ctx)
+	if err != nil {
+		// the server is most likely wound down, which is not worth an alert
+		log.From(ctx).Debug("sampling resource usage", zap.Error(err))
+		return alerted
+	}
+	ratio, ok := usage.MemoryLimitRatio()
+	if !ok {
+		log.From(ctx).Debug("skipping resource alert without memory limit", zap.String("pod", usage.Pod))
+		return false
+	}
+	if ratio < a.Threshold {
+		return false
+	}
+	if alerted {
+		return true
+	}
+
+	log.From(ctx).Info("alerting memory usage", zap.String("pod", usage.Pod), zap.Float64("ratio", ratio))
+	if _, err := session.ChannelMessageSendEmbed(a.ChannelID, &discordgo.MessageEmbed{
+		Title:       "High Memory Usage",
+		Description: fmt.Sprintf("A server container is using %.f%% of its memory limit and might run out of memory soon.", ratio*100),
+		Fields:      usageFields(usage),
+	}); err != nil {
+		log.From(ctx).Error("posting resource alert", zap.Error(err))
+		return false
+	}
+	return true
+}
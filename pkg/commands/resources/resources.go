@@ -0,0 +1,150 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/playnet-public/mc-bot/pkg/bot/debounce"
+	"github.com/playnet-public/mc-bot/pkg/bot/extract"
+	"github.com/playnet-public/mc-bot/pkg/bot/responses"
+	"github.com/playnet-public/mc-bot/pkg/capability"
+	"github.com/seibert-media/golibs/log"
+	"go.uber.org/zap"
+)
+
+const (
+	name      = "resources"
+	refreshID = "refresh_resources"
+	none      = "<none>"
+)
+
+// Command for showing the resource usage of the server
+type Command struct {
+	Usage capability.UsageSource
+}
+
+// Name of the Command
+func (c Command) Name() string {
+	return name
+}
+
+// Build the Command for installing
+func (c Command) Build() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        name,
+		Description: "Show the CPU and memory usage of the server",
+		Options:     []*discordgo.ApplicationCommandOption{},
+	}
+}
+
+// MatchInteraction returns if the Command can handle the interaction
+func (c Command) MatchInteraction(id string) bool {
+	return id == refreshID
+}
+
+// HandleCommand handles the initial event
+func (c Command) HandleCommand(ctx context.Context, session *discordgo.Session, i *discordgo.InteractionCreate) error {
+	return c.refreshUsage(ctx, session, i, discordgo.InteractionResponseChannelMessageWithSource)
+}
+
+const debounceSeconds = 10
+
+// HandleInteractions handles follow-up interactions with the original message
+func (c Command) HandleInteractions(ctx context.Context, session *discordgo.Session, i *discordgo.InteractionCreate) error {
+	debouncer := debounce.InteractionTimestamp(extract.EmbedFieldValue(0, 0), debounceSeconds*time.Second)
+	if shouldDebounce, duration := debouncer(i); shouldDebounce {
+		return responses.NewInteractionEphemeral(session, i, fmt.Sprintf("Please wait at least %.f seconds before retrying.", duration.Seconds()))
+	}
+	return c.refreshUsage(ctx, session, i, discordgo.InteractionResponseUpdateMessage)
+}
+
+func (c Command) refreshUsage(ctx context.Context, session *discordgo.Session, i *discordgo.InteractionCreate, responseType discordgo.InteractionResponseType) error {
+	usage, err := c.Usage.Usage(ctx)
+	if err != nil {
+		log.From(ctx).Error("getting resource usage", zap.Error(err))
+		return responses.NewInteractionError(session, i, fmt.Errorf("failed to get resource usage: %w", err))
+	}
+
+	fields := []*discordgo.MessageEmbedField{
+		{
+			Name:  "Last Refresh",
+			Value: debounce.NewTimestampFor(time.Now()),
+		},
+	}
+	fields = append(fields, usageFields(usage)...)
+
+	return session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: responseType,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{
+				{
+					Title:       "Server Resources",
+					Description: "Usage compared to the requests and limits of the server pod. Click Refresh to get the current usage.",
+					Fields:      fields,
+				},
+			},
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.Button{
+							Emoji: discordgo.ComponentEmoji{
+								Name: "♻️",
+							},
+							Label:    "Refresh",
+							Style:    discordgo.SecondaryButton,
+							CustomID: refreshID,
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+func usageFields(usage capability.ResourceUsage) []*discordgo.MessageEmbedField {
+	node := none
+	if len(usage.Node) > 0 {
+		node = usage.Node
+	}
+	fields := []*discordgo.MessageEmbedField{
+		{Name: "Pod", Value: usage.Pod, Inline: true},
+		{Name: "Node", Value: node, Inline: true},
+	}
+	for _, container := range usage.Containers {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name: fmt.Sprintf("Container %s", container.Name),
+			Value: strings.Join([]string{
+				"CPU: " + compare(container.CPU, container.CPURequest, container.CPULimit, formatCPU),
+				"Memory: " + compare(container.Memory, container.MemoryRequest, container.MemoryLimit, formatMemory),
+			}, "\n"),
+		})
+	}
+	return fields
+}
+
+// compare the usage with the request and limit, skipping unset ones
+func compare(usage, request, limit int64, format func(int64) string) string {
+	parts := []string{format(usage)}
+	if request > 0 {
+		parts = append(parts, fmt.Sprintf("%.f%% of %s request", percent(usage, request), format(request)))
+	}
+	if limit > 0 {
+		parts = append(parts, fmt.Sprintf("%.f%% of %s limit", percent(usage, limit), format(limit)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func percent(usage, of int64) float64 {
+	return float64(usage) / float64(of) * 100
+}
+
+func formatCPU(millicores int64) string {
+	return fmt.Sprintf("%dm", millicores)
+}
+
+func formatMemory(bytes int64) string {
+	return fmt.Sprintf("%.0fMi", float64(bytes)/(1<<20))
+}
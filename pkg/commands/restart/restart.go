@@ -2,14 +2,17 @@ package restart
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/playnet-public/mc-bot/pkg/bot/debounce"
 	"github.com/playnet-public/mc-bot/pkg/bot/extract"
 	"github.com/playnet-public/mc-bot/pkg/bot/responses"
+	"github.com/playnet-public/mc-bot/pkg/capability"
 	"github.com/seibert-media/golibs/log"
 	"go.uber.org/zap"
 )
@@ -19,21 +22,22 @@ const (
 	overrideID = "override_restart"
 	retryID    = "retry_restart"
 	abortID    = "abort_restart"
+	voteID     = "vote_restart"
 )
 
 // Command for restarting a server on user requests
 type Command struct {
 	OverriderRole string
 
-	PlayerCounter interface {
-		CountPlayers(ctx context.Context) (int, error)
-	}
-	Restarter interface {
-		Restart(ctx context.Context) error
-	}
-	MessageSender interface {
-		SendMessage(ctx context.Context, msg string) error
-	}
+	PlayerCounter capability.PlayerCounter
+	Restarter     capability.Restarter
+	// Tracker restarts the server instead of Restarter if set, reporting each phase until it's back
+	Tracker capability.RestartTracker
+	// MessageSender announces restart requests to the players if set
+	MessageSender capability.MessageSender
+
+	// Vote enables community votes to restart without an approver if set
+	Vote *Vote
 }
 
 // Name of the Command
@@ -54,19 +58,22 @@ func (c Command) Build() *discordgo.ApplicationCommand {
 func (c Command) MatchInteraction(id string) bool {
 	return id == overrideID ||
 		id == abortID ||
-		id == retryID
+		id == retryID ||
+		id == voteID
 }
 
 // HandleCommand handles the initial event
 func (c Command) HandleCommand(ctx context.Context, session *discordgo.Session, i *discordgo.InteractionCreate) error {
-	var mention string
-	if i.Member != nil && i.Member.User != nil {
-		mention = i.Member.User.String()
-	} else if i.User != nil {
-		mention = i.User.String()
-	}
-	if err := c.MessageSender.SendMessage(ctx, fmt.Sprintf("%s is requesting a server restart. You can leave the server to comply with their request.", mention)); err != nil {
-		log.From(ctx).Error("sending restart message", zap.Error(err))
+	if c.MessageSender != nil {
+		var mention string
+		if i.Member != nil && i.Member.User != nil {
+			mention = i.Member.User.String()
+		} else if i.User != nil {
+			mention = i.User.String()
+		}
+		if err := c.MessageSender.SendMessage(ctx, fmt.Sprintf("%s is requesting a server restart. You can leave the server to comply with their request.", mention)); err != nil {
+			log.From(ctx).Error("sending restart message", zap.Error(err))
+		}
 	}
 	return c.tryRestart(ctx, session, i, discordgo.InteractionResponseChannelMessageWithSource)
 }
@@ -86,6 +93,8 @@ func (c Command) HandleInteractions(ctx context.Context, session *discordgo.Sess
 			return responses.NewInteractionEphemeral(session, i, fmt.Sprintf("Please wait at least %.f seconds before retrying.", duration.Seconds()))
 		}
 		return c.tryRestart(ctx, session, i, discordgo.InteractionResponseUpdateMessage)
+	case voteID:
+		return c.handleVote(ctx, session, i)
 	default:
 		return nil
 	}
@@ -98,56 +107,84 @@ func (c Command) tryRestart(ctx context.Context, session *discordgo.Session, i *
 	}
 
 	if playerCount < 1 {
+		c.closeVote(i)
 		return c.restartNow(ctx, session, i, responseType)
 	}
 
+	var votes *voteState
+	if c.Vote != nil {
+		current := c.Vote.current(i.Message)
+		votes = &current
+	}
+
+	return c.respondWaiting(session, i, responseType, playerCount, votes)
+}
+
+func (c Command) respondWaiting(session *discordgo.Session, i *discordgo.InteractionCreate, responseType discordgo.InteractionResponseType, playerCount int, votes *voteState) error {
+	fields := []*discordgo.MessageEmbedField{
+		{
+			Name:  "Players",
+			Value: strconv.Itoa(playerCount),
+		},
+		{
+			Name:  "Last try",
+			Value: debounce.NewTimestampFor(time.Now()),
+		},
+	}
+	buttons := []discordgo.MessageComponent{
+		discordgo.Button{
+			Emoji: discordgo.ComponentEmoji{
+				Name: "⚠️",
+			},
+			Label:    "Override",
+			Style:    discordgo.DangerButton,
+			CustomID: overrideID,
+		},
+		discordgo.Button{
+			Emoji: discordgo.ComponentEmoji{
+				Name: "🛑",
+			},
+			Label:    "Abort",
+			Style:    discordgo.SecondaryButton,
+			CustomID: abortID,
+		},
+		discordgo.Button{
+			Emoji: discordgo.ComponentEmoji{
+				Name: "🔃",
+			},
+			Label:    "Retry",
+			Style:    discordgo.PrimaryButton,
+			CustomID: retryID,
+		},
+	}
+	description := "The server is waiting for all players to leave. Retry when it's empty."
+
+	if votes != nil {
+		description = "The server is waiting for all players to leave. Retry when it's empty or vote to restart anyway."
+		fields = append(fields, votes.fields(c.Vote.required(playerCount))...)
+		buttons = append(buttons, discordgo.Button{
+			Emoji: discordgo.ComponentEmoji{
+				Name: "🗳️",
+			},
+			Label:    "Vote",
+			Style:    discordgo.SuccessButton,
+			CustomID: voteID,
+		})
+	}
+
 	return session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: responseType,
 		Data: &discordgo.InteractionResponseData{
 			Embeds: []*discordgo.MessageEmbed{
 				{
 					Title:       "Requesting Restart",
-					Description: "The server is waiting for all players to leave. Retry when it's empty.",
-					Fields: []*discordgo.MessageEmbedField{
-						{
-							Name:  "Players",
-							Value: strconv.Itoa(playerCount),
-						},
-						{
-							Name:  "Last try",
-							Value: debounce.NewTimestampFor(time.Now()),
-						},
-					},
+					Description: description,
+					Fields:      fields,
 				},
 			},
 			Components: []discordgo.MessageComponent{
 				discordgo.ActionsRow{
-					Components: []discordgo.MessageComponent{
-						discordgo.Button{
-							Emoji: discordgo.ComponentEmoji{
-								Name: "⚠️",
-							},
-							Label:    "Override",
-							Style:    discordgo.DangerButton,
-							CustomID: overrideID,
-						},
-						discordgo.Button{
-							Emoji: discordgo.ComponentEmoji{
-								Name: "🛑",
-							},
-							Label:    "Abort",
-							Style:    discordgo.SecondaryButton,
-							CustomID: abortID,
-						},
-						discordgo.Button{
-							Emoji: discordgo.ComponentEmoji{
-								Name: "🔃",
-							},
-							Label:    "Retry",
-							Style:    discordgo.PrimaryButton,
-							CustomID: retryID,
-						},
-					},
+					Components: buttons,
 				},
 			},
 		},
@@ -159,10 +196,12 @@ func (c Command) handleOverride(ctx context.Context, session *discordgo.Session,
 		return c.respondNotOverrider(session, i)
 	}
 
+	c.closeVote(i)
 	return c.restartNow(ctx, session, i, discordgo.InteractionResponseUpdateMessage)
 }
 
 func (c Command) handleAbort(session *discordgo.Session, i *discordgo.InteractionCreate) error {
+	c.closeVote(i)
 	return session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseUpdateMessage,
 		Data: &discordgo.InteractionResponseData{
@@ -178,6 +217,9 @@ func (c Command) handleAbort(session *discordgo.Session, i *discordgo.Interactio
 }
 
 func (c Command) restartNow(ctx context.Context, session *discordgo.Session, i *discordgo.InteractionCreate, responseType discordgo.InteractionResponseType) error {
+	if c.Tracker != nil {
+		return c.restartTracked(ctx, session, i, responseType)
+	}
 	if err := c.Restarter.Restart(ctx); err != nil {
 		log.From(ctx).Error("restarting server", zap.Error(err))
 		return responses.NewInteractionError(session, i, fmt.Errorf("failed to restart the server: %w", err))
@@ -197,6 +239,116 @@ func (c Command) restartNow(ctx context.Context, session *discordgo.Session, i *
 	})
 }
 
+// restartTracked restarts the server, editing the response on every phase change.
+// Tracking stops at responses.MaxTimeout, as the response can't be edited afterwards
+func (c Command) restartTracked(ctx context.Context, session *discordgo.Session, i *discordgo.InteractionCreate, responseType discordgo.InteractionResponseType) error {
+	if err := session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: responseType,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{restartingEmbed(capability.PhaseTerminating)},
+			Components: []discordgo.MessageComponent{},
+		},
+	}); err != nil {
+		return err
+	}
+
+	trackCtx, cancel := context.WithTimeout(ctx, responses.MaxTimeout)
+	defer cancel()
+	err := c.Tracker.RestartTracked(trackCtx, func(phase capability.Phase) {
+		log.From(ctx).Info("restart progressing", zap.String("phase", string(phase)))
+		if err := c.edit(session, i, restartingEmbed(phase)); err != nil {
+			log.From(ctx).Error("updating restart response", zap.Error(err))
+		}
+	})
+	if err != nil {
+		log.From(ctx).Error("restarting server", zap.Error(err))
+		return c.respondFailed(session, i, err)
+	}
+
+	if err := c.edit(session, i, &discordgo.MessageEmbed{
+		Title:       "Server is back",
+		Description: "The server restarted and is ready to play.",
+		Fields:      []*discordgo.MessageEmbedField{},
+	}); err != nil {
+		return err
+	}
+	_, err = session.FollowupMessageCreate(session.State.User.ID, i.Interaction, false, &discordgo.WebhookParams{
+		Content: mentionOf(i) + "the server is back!",
+	})
+	return err
+}
+
+// respondFailed reports a failed restart including the events of the stuck pod and
+// mentions the approvers, as the server needs attention
+func (c Command) respondFailed(session *discordgo.Session, i *discordgo.InteractionCreate, err error) error {
+	fields := []*discordgo.MessageEmbedField{
+		{
+			Name:  "Error",
+			Value: err.Error(),
+		},
+	}
+	var failure *capability.RestartFailure
+	if errors.As(err, &failure) {
+		eventsValue := "<none>"
+		if len(failure.Events) > 0 {
+			eventsValue = fmt.Sprintf("```\n%s\n```", strings.Join(failure.Events, "\n"))
+		}
+		fields = []*discordgo.MessageEmbedField{
+			{
+				Name:  "Last Phase",
+				Value: string(failure.Phase),
+			},
+			{
+				Name:  "Events",
+				Value: eventsValue,
+			},
+		}
+	}
+
+	if err := c.edit(session, i, &discordgo.MessageEmbed{
+		Title:       "Restart failed",
+		Description: "The server did not come back in time. Please check on it.",
+		Fields:      fields,
+	}); err != nil {
+		return err
+	}
+	_, err = session.FollowupMessageCreate(session.State.User.ID, i.Interaction, false, &discordgo.WebhookParams{
+		Content: fmt.Sprintf("<@&%s> the restart failed, please check on the server.", c.OverriderRole),
+	})
+	return err
+}
+
+func (c Command) edit(session *discordgo.Session, i *discordgo.InteractionCreate, embed *discordgo.MessageEmbed) error {
+	_, err := session.InteractionResponseEdit(session.State.User.ID, i.Interaction, &discordgo.WebhookEdit{
+		Embeds:     []*discordgo.MessageEmbed{embed},
+		Components: []discordgo.MessageComponent{},
+	})
+	return err
+}
+
+func restartingEmbed(phase capability.Phase) *discordgo.MessageEmbed {
+	return &discordgo.MessageEmbed{
+		Title:       "Restarting Server",
+		Description: "The server will be back shortly. Please stand by.",
+		Fields: []*discordgo.MessageEmbedField{
+			{
+				Name:  "Phase",
+				Value: string(phase),
+			},
+		},
+	}
+}
+
+// mentionOf the user of the interaction as the start of a sentence
+func mentionOf(i *discordgo.InteractionCreate) string {
+	if i.Member != nil {
+		return i.Member.Mention() + ", "
+	} else if i.User != nil {
+		return i.User.Mention() + ", "
+	}
+	return ""
+}
+
 func (c Command) isApprover(member *discordgo.Member) bool {
 	for _, role := range member.Roles {
 		if role == c.OverriderRole {
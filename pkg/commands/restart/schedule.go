@@ -0,0 +1,61 @@
+package restart
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/playnet-public/mc-bot/pkg/capability"
+	"github.com/seibert-media/golibs/log"
+	"go.uber.org/zap"
+)
+
+// DefaultDelay gives online players time to leave before scheduled restarts
+const DefaultDelay = 5 * time.Minute
+
+// Scheduler restarts the server to apply changes, e.g. to its settings, without surprising online players
+type Scheduler struct {
+	Restarter     capability.Restarter
+	PlayerCounter capability.PlayerCounter
+	// MessageSender announces delayed restarts to the players if set
+	MessageSender capability.MessageSender
+	// Delay gives online players time to leave before the restart, DefaultDelay is used if unset
+	Delay time.Duration
+}
+
+// Schedule restarts the server right away if it's empty or after the delay otherwise,
+// returning a description for the response
+func (s Scheduler) Schedule(ctx context.Context) string {
+	delay := s.Delay
+	if delay <= 0 {
+		delay = DefaultDelay
+	}
+
+	// players might be online if they can't be counted, so only a confirmed empty server is restarted right away
+	playerCount, err := s.PlayerCounter.CountPlayers(ctx)
+	if err == nil && playerCount < 1 {
+		if err := s.Restarter.Restart(ctx); err != nil {
+			log.From(ctx).Error("restarting server", zap.Error(err))
+			return fmt.Sprintf("Restarting the server failed: %s", err)
+		}
+		return "The server is restarting."
+	}
+	if err != nil {
+		log.From(ctx).Error("counting players, delaying restart", zap.Error(err))
+	}
+
+	if s.MessageSender != nil {
+		if err := s.MessageSender.SendMessage(ctx, fmt.Sprintf("The server restarts in %s to apply a settings change.", delay)); err != nil {
+			log.From(ctx).Error("announcing restart", zap.Error(err))
+		}
+	}
+	time.AfterFunc(delay, func() {
+		if err := s.Restarter.Restart(ctx); err != nil {
+			log.From(ctx).Error("restarting server", zap.Error(err))
+		}
+	})
+	if err != nil {
+		return fmt.Sprintf("The server restarts in %s, as the online players couldn't be counted.", delay)
+	}
+	return fmt.Sprintf("The server restarts in %s while %d players are online.", delay, playerCount)
+}
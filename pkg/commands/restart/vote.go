@@ -0,0 +1,298 @@
+package restart
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/playnet-public/mc-bot/pkg/bot/debounce"
+	"github.com/playnet-public/mc-bot/pkg/bot/extract"
+	"github.com/playnet-public/mc-bot/pkg/bot/responses"
+	"github.com/playnet-public/mc-bot/pkg/capability"
+	"github.com/seibert-media/golibs/log"
+	"go.uber.org/zap"
+)
+
+// Vote configures community votes allowing a restart without an approver
+type Vote struct {
+	// Quorum of votes required for the restart. If unset, a majority of the
+	// online players has to agree
+	Quorum int
+	// Timeout after which the vote expires
+	Timeout time.Duration
+
+	// PlayerLister is used to verify voters against the online players if set.
+	// Voters are matched by their Discord nickname or username
+	PlayerLister capability.PlayerLister
+
+	// l guards ballots, which hold the running votes keyed by the ID of their message
+	l       sync.Mutex
+	ballots map[string]*voteState
+}
+
+// required votes for the restart with playerCount players online
+func (v *Vote) required(playerCount int) int {
+	if v.Quorum > 0 {
+		return v.Quorum
+	}
+	return playerCount/2 + 1
+}
+
+const (
+	votesFieldIndex     = 2
+	votersFieldIndex    = 3
+	voteStartFieldIndex = 4
+	noVoters            = "<none>"
+)
+
+var mentionRegex = regexp.MustCompile(`<@!?([0-9]+)>`)
+
+// voteState is kept in memory while the vote is running and mirrored to the embed
+// of the restart request, so votes survive restarts of the bot
+type voteState struct {
+	startedAt string
+	voters    []string
+	timer     *time.Timer
+}
+
+// current vote of message, which is either running in memory or restored from the embed
+func (v *Vote) current(m *discordgo.Message) voteState {
+	v.l.Lock()
+	defer v.l.Unlock()
+
+	if m != nil {
+		if state, ok := v.ballots[m.ID]; ok {
+			return voteState{startedAt: state.startedAt, voters: append([]string{}, state.voters...)}
+		}
+	}
+	return *v.stateFrom(m)
+}
+
+// cast the vote of userID on the request in message i.Message, starting the expiry timer
+// for votes not yet in memory. v.l has to be held by the caller
+func (v *Vote) cast(ctx context.Context, session *discordgo.Session, i *discordgo.InteractionCreate, userID string) (*voteState, error) {
+	state, ok := v.ballots[i.Message.ID]
+	if !ok {
+		state = v.stateFrom(i.Message)
+		active, remaining := debounce.OnTimestamp(state.startedAt, v.Timeout)
+		if !active {
+			return nil, errVoteExpired
+		}
+		if v.ballots == nil {
+			v.ballots = make(map[string]*voteState)
+		}
+		channelID, messageID := i.ChannelID, i.Message.ID
+		state.timer = time.AfterFunc(remaining, func() {
+			v.expire(ctx, session, channelID, messageID)
+		})
+		v.ballots[messageID] = state
+	}
+
+	if state.hasVoted(userID) {
+		return nil, errAlreadyVoted
+	}
+	state.voters = append(state.voters, userID)
+	return state, nil
+}
+
+// close the vote of messageID, e.g. after it passed. v.l has to be held by the caller
+func (v *Vote) close(messageID string) {
+	if state, ok := v.ballots[messageID]; ok {
+		state.timer.Stop()
+		delete(v.ballots, messageID)
+	}
+}
+
+// closeVote of the request in i.Message if it's running, as the request ended without it passing
+func (c Command) closeVote(i *discordgo.InteractionCreate) {
+	if c.Vote == nil || i.Message == nil {
+		return
+	}
+	c.Vote.l.Lock()
+	defer c.Vote.l.Unlock()
+	c.Vote.close(i.Message.ID)
+}
+
+// expire the vote of messageID, replacing the request with a notice
+func (v *Vote) expire(ctx context.Context, session *discordgo.Session, channelID, messageID string) {
+	v.l.Lock()
+	_, ok := v.ballots[messageID]
+	delete(v.ballots, messageID)
+	v.l.Unlock()
+	if !ok {
+		return
+	}
+
+	log.From(ctx).Info("restart vote expired", zap.String("message", messageID))
+	if _, err := session.ChannelMessageEditComplex(&discordgo.MessageEdit{
+		ID:         messageID,
+		Channel:    channelID,
+		Components: []discordgo.MessageComponent{},
+		Embed:      voteExpiredEmbed(),
+	}); err != nil {
+		log.From(ctx).Error("updating expired restart vote", zap.Error(err))
+	}
+}
+
+var (
+	errVoteExpired  = errors.New("vote expired")
+	errAlreadyVoted = errors.New("already voted")
+)
+
+// stateFrom extracts the current vote from message or starts a new one
+func (v *Vote) stateFrom(m *discordgo.Message) *voteState {
+	if m == nil {
+		return &voteState{startedAt: debounce.NewTimestampFor(time.Now())}
+	}
+	startedAt, err := extract.EmbedFieldValue(0, voteStartFieldIndex)(m)
+	if err != nil {
+		return &voteState{startedAt: debounce.NewTimestampFor(time.Now())}
+	}
+	state := &voteState{startedAt: startedAt}
+	if voters, err := extract.EmbedFieldValue(0, votersFieldIndex)(m); err == nil {
+		for _, match := range mentionRegex.FindAllStringSubmatch(voters, -1) {
+			state.voters = append(state.voters, match[1])
+		}
+	}
+	return state
+}
+
+func (s voteState) hasVoted(userID string) bool {
+	for _, voter := range s.voters {
+		if voter == userID {
+			return true
+		}
+	}
+	return false
+}
+
+func (s voteState) fields(required int) []*discordgo.MessageEmbedField {
+	voters := noVoters
+	if len(s.voters) > 0 {
+		mentions := make([]string, 0, len(s.voters))
+		for _, voter := range s.voters {
+			mentions = append(mentions, fmt.Sprintf("<@%s>", voter))
+		}
+		voters = strings.Join(mentions, ", ")
+	}
+
+	return []*discordgo.MessageEmbedField{
+		{
+			Name:   "Votes",
+			Value:  fmt.Sprintf("%d/%d", len(s.voters), required),
+			Inline: true,
+		},
+		{
+			Name:   "Voters",
+			Value:  voters,
+			Inline: true,
+		},
+		{
+			Name:  "Vote started",
+			Value: s.startedAt,
+		},
+	}
+}
+
+func (c Command) handleVote(ctx context.Context, session *discordgo.Session, i *discordgo.InteractionCreate) error {
+	if c.Vote == nil {
+		return responses.NewInteractionEphemeral(session, i, "Voting is not enabled for restarts.")
+	}
+	if i.Member == nil || i.Member.User == nil {
+		return responses.NewInteractionEphemeral(session, i, "Only members of this server can vote.")
+	}
+
+	if c.Vote.PlayerLister != nil {
+		online, err := c.isOnline(ctx, i.Member)
+		if err != nil {
+			return responses.NewInteractionError(session, i, fmt.Errorf("failed verifying voter: %w", err))
+		}
+		if !online {
+			return responses.NewInteractionEphemeral(session, i, "Only players currently online on the server can vote. Make sure your Discord nickname matches your in-game name.")
+		}
+	}
+
+	playerCount, err := c.PlayerCounter.CountPlayers(ctx)
+	if err != nil {
+		return responses.NewInteractionError(session, i, fmt.Errorf("failed getting player count: %w", err))
+	}
+
+	passed, voteCount, err := c.countVote(ctx, session, i, playerCount)
+	if err != nil || !passed {
+		return err
+	}
+
+	if c.MessageSender != nil && playerCount > 0 {
+		if err := c.MessageSender.SendMessage(ctx, fmt.Sprintf("The restart vote passed with %d votes. The server is restarting now.", voteCount)); err != nil {
+			log.From(ctx).Error("sending vote message", zap.Error(err))
+		}
+	}
+	return c.restartNow(ctx, session, i, discordgo.InteractionResponseUpdateMessage)
+}
+
+// countVote of the interacting member, responding unless the vote passed. Concurrent votes
+// are serialized, so none of them gets lost
+func (c Command) countVote(ctx context.Context, session *discordgo.Session, i *discordgo.InteractionCreate, playerCount int) (bool, int, error) {
+	c.Vote.l.Lock()
+	defer c.Vote.l.Unlock()
+
+	if playerCount < 1 {
+		c.Vote.close(i.Message.ID)
+		return true, 0, nil
+	}
+
+	votes, err := c.Vote.cast(ctx, session, i, i.Member.User.ID)
+	switch {
+	case errors.Is(err, errVoteExpired):
+		return false, 0, c.respondVoteExpired(session, i)
+	case errors.Is(err, errAlreadyVoted):
+		return false, 0, responses.NewInteractionEphemeral(session, i, "You already voted for this restart.")
+	case err != nil:
+		return false, 0, err
+	}
+
+	required := c.Vote.required(playerCount)
+	log.From(ctx).Info("counting restart vote", zap.Int("votes", len(votes.voters)), zap.Int("required", required))
+	if len(votes.voters) < required {
+		return false, len(votes.voters), c.respondWaiting(session, i, discordgo.InteractionResponseUpdateMessage, playerCount, votes)
+	}
+
+	c.Vote.close(i.Message.ID)
+	return true, len(votes.voters), nil
+}
+
+// isOnline returns if member's nickname or username is on the player list
+func (c Command) isOnline(ctx context.Context, member *discordgo.Member) (bool, error) {
+	_, players, err := c.Vote.PlayerLister.Players(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, player := range players {
+		if strings.EqualFold(player, member.Nick) || strings.EqualFold(player, member.User.Username) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (c Command) respondVoteExpired(session *discordgo.Session, i *discordgo.InteractionCreate) error {
+	return session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Components: []discordgo.MessageComponent{},
+			Embeds:     []*discordgo.MessageEmbed{voteExpiredEmbed()},
+		},
+	})
+}
+
+func voteExpiredEmbed() *discordgo.MessageEmbed {
+	return &discordgo.MessageEmbed{
+		Title:       "Vote expired",
+		Description: "Not enough players voted for the restart in time. Use /restart to request a new one.",
+	}
+}
@@ -0,0 +1,147 @@
+package restart
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// discord records the requests of a session to the Discord API, answering each of them successfully
+type discord struct {
+	l        sync.Mutex
+	requests []string
+}
+
+func (d *discord) RoundTrip(r *http.Request) (*http.Response, error) {
+	d.l.Lock()
+	d.requests = append(d.requests, r.Method+" "+r.URL.Path)
+	d.l.Unlock()
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader("{}")),
+		Request:    r,
+	}, nil
+}
+
+// edited returns if the message was edited through the channel, as done by expiring votes
+func (d *discord) edited(channelID, messageID string) bool {
+	d.l.Lock()
+	defer d.l.Unlock()
+	for _, request := range d.requests {
+		if strings.HasSuffix(request, "/channels/"+channelID+"/messages/"+messageID) && strings.HasPrefix(request, http.MethodPatch) {
+			return true
+		}
+	}
+	return false
+}
+
+func newSession(t *testing.T) (*discordgo.Session, *discord) {
+	session, err := discordgo.New("Bot token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &discord{}
+	session.Client = &http.Client{Transport: d}
+	return session, d
+}
+
+type fakeServer struct {
+	players  int
+	restarts int
+}
+
+func (s *fakeServer) CountPlayers(ctx context.Context) (int, error) {
+	return s.players, nil
+}
+
+func (s *fakeServer) Restart(ctx context.Context) error {
+	s.restarts++
+	return nil
+}
+
+func TestVoteEndedRequestDoesNotExpire(t *testing.T) {
+	tests := []struct {
+		name string
+		// end the request after the vote started, nil to let the vote run out
+		end             func(ctx context.Context, c Command, session *discordgo.Session, i *discordgo.InteractionCreate) error
+		expectedEdited  bool
+		expectedRestart int
+	}{
+		{
+			name:           "expired",
+			expectedEdited: true,
+		},
+		{
+			name: "overridden",
+			end: func(ctx context.Context, c Command, session *discordgo.Session, i *discordgo.InteractionCreate) error {
+				return c.handleOverride(ctx, session, i)
+			},
+			expectedRestart: 1,
+		},
+		{
+			name: "aborted",
+			end: func(ctx context.Context, c Command, session *discordgo.Session, i *discordgo.InteractionCreate) error {
+				return c.handleAbort(session, i)
+			},
+		},
+		{
+			name: "retried on an empty server",
+			end: func(ctx context.Context, c Command, session *discordgo.Session, i *discordgo.InteractionCreate) error {
+				c.PlayerCounter.(*fakeServer).players = 0
+				return c.tryRestart(ctx, session, i, discordgo.InteractionResponseUpdateMessage)
+			},
+			expectedRestart: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+			session, d := newSession(t)
+			server := &fakeServer{players: 3}
+			// votes start at the current second, so they run out within the timeout
+			c := Command{
+				OverriderRole: "approver",
+				PlayerCounter: server,
+				Restarter:     server,
+				Vote:          &Vote{Timeout: time.Second + 100*time.Millisecond},
+			}
+			i := &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+				ID:        "interaction",
+				Token:     "token",
+				ChannelID: "channel",
+				Message:   &discordgo.Message{ID: "message", ChannelID: "channel"},
+				Member:    &discordgo.Member{User: &discordgo.User{ID: "voter"}, Roles: []string{"approver"}},
+			}}
+
+			passed, _, err := c.countVote(ctx, session, i, server.players)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if passed {
+				t.Fatal("expected the vote to keep running")
+			}
+			if tt.end != nil {
+				if err := tt.end(ctx, c, session, i); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			time.Sleep(c.Vote.Timeout + 200*time.Millisecond)
+			if edited := d.edited("channel", "message"); edited != tt.expectedEdited {
+				t.Errorf("expected the request to be edited as expired %t, got %t", tt.expectedEdited, edited)
+			}
+			if server.restarts != tt.expectedRestart {
+				t.Errorf("expected %d restarts, got %d", tt.expectedRestart, server.restarts)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package serverinfo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/playnet-public/mc-bot/pkg/bot/debounce"
+	"github.com/playnet-public/mc-bot/pkg/bot/extract"
+	"github.com/playnet-public/mc-bot/pkg/bot/responses"
+	"github.com/playnet-public/mc-bot/pkg/capability"
+	"github.com/seibert-media/golibs/log"
+	"go.uber.org/zap"
+)
+
+const (
+	name      = "serverinfo"
+	refreshID = "refresh_serverinfo"
+
+	// maxRulesLength keeps the rules within the 1024 characters of a field
+	maxRulesLength = 1000
+)
+
+// Command for showing the details a server reports about itself
+type Command struct {
+	Querier capability.Querier
+	// RulesLister is used to show the rules the server reports if set
+	RulesLister capability.RulesLister
+}
+
+// Name of the Command
+func (c Command) Name() string {
+	return name
+}
+
+// Build the Command for installing
+func (c Command) Build() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        name,
+		Description: "Show the name, version and player slots of the server",
+		Options:     []*discordgo.ApplicationCommandOption{},
+	}
+}
+
+// MatchInteraction returns if the Command can handle the interaction
+func (c Command) MatchInteraction(id string) bool {
+	return id == refreshID
+}
+
+// HandleCommand handles the initial event
+func (c Command) HandleCommand(ctx context.Context, session *discordgo.Session, i *discordgo.InteractionCreate) error {
+	return c.refreshInfo(ctx, session, i, discordgo.InteractionResponseChannelMessageWithSource)
+}
+
+const debounceSeconds = 10
+
+// HandleInteractions handles follow-up interactions with the original message
+func (c Command) HandleInteractions(ctx context.Context, session *discordgo.Session, i *discordgo.InteractionCreate) error {
+	debouncer := debounce.InteractionTimestamp(extract.EmbedFieldValue(0, 0), debounceSeconds*time.Second)
+	if shouldDebounce, duration := debouncer(i); shouldDebounce {
+		return responses.NewInteractionEphemeral(session, i, fmt.Sprintf("Please wait at least %.f seconds before retrying.", duration.Seconds()))
+	}
+	return c.refreshInfo(ctx, session, i, discordgo.InteractionResponseUpdateMessage)
+}
+
+func (c Command) refreshInfo(ctx context.Context, session *discordgo.Session, i *discordgo.InteractionCreate, responseType discordgo.InteractionResponseType) error {
+	details, err := c.Querier.Details(ctx)
+	if err != nil {
+		log.From(ctx).Info("querying server info", zap.Error(err))
+		return responses.NewInteractionError(session, i, fmt.Errorf("failed to query the server: %w", err))
+	}
+
+	title := details.Name
+	if len(title) < 1 {
+		title = "Server Info"
+	}
+	password := "No"
+	if details.Password {
+		password = "Yes"
+	}
+
+	fields := []*discordgo.MessageEmbedField{
+		{Name: "Last Refresh", Value: debounce.NewTimestampFor(time.Now())},
+		{Name: "Game", Value: valueOrNone(details.Game), Inline: true},
+		{Name: "Map", Value: valueOrNone(details.Map), Inline: true},
+		{Name: "Version", Value: valueOrNone(details.Version), Inline: true},
+		{Name: "Players", Value: fmt.Sprintf("%d/%d", details.Players, details.MaxPlayers), Inline: true},
+		{Name: "Password", Value: password, Inline: true},
+		{Name: "Ping", Value: details.Ping.Round(time.Millisecond).String(), Inline: true},
+	}
+	if c.RulesLister != nil {
+		fields = append(fields, c.rulesField(ctx))
+	}
+
+	return session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: responseType,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{
+				{
+					Title:       title,
+					Description: "Click Refresh to get the current info.",
+					Fields:      fields,
+				},
+			},
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.Button{
+							Emoji: discordgo.ComponentEmoji{
+								Name: "♻️",
+							},
+							Label:    "Refresh",
+							Style:    discordgo.SecondaryButton,
+							CustomID: refreshID,
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+func (c Command) rulesField(ctx context.Context) *discordgo.MessageEmbedField {
+	rules, err := c.RulesLister.Rules(ctx)
+	if err != nil {
+		// not all servers answer rule queries
+		log.From(ctx).Info("querying server rules", zap.Error(err))
+		return &discordgo.MessageEmbedField{Name: "Rules", Value: "<unavailable>"}
+	}
+	if len(rules) < 1 {
+		return &discordgo.MessageEmbedField{Name: "Rules", Value: "<none>"}
+	}
+
+	keys := make([]string, 0, len(rules))
+	for key := range rules {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	list := strings.Builder{}
+	for _, key := range keys {
+		line := fmt.Sprintf("%s=%s\n", key, rules[key])
+		if list.Len()+len(line) > maxRulesLength {
+			list.WriteString("...\n")
+			break
+		}
+		list.WriteString(line)
+	}
+	return &discordgo.MessageEmbedField{
+		Name:  "Rules",
+		Value: fmt.Sprintf("```\n%s```", strings.ReplaceAll(list.String(), "```", "'''")),
+	}
+}
+
+func valueOrNone(value string) string {
+	if len(value) < 1 {
+		return "<none>"
+	}
+	return value
+}
@@ -0,0 +1,179 @@
+package status
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/playnet-public/mc-bot/pkg/bot/debounce"
+	"github.com/playnet-public/mc-bot/pkg/bot/extract"
+	"github.com/playnet-public/mc-bot/pkg/bot/responses"
+	"github.com/playnet-public/mc-bot/pkg/capability"
+	"github.com/seibert-media/golibs/log"
+	"go.uber.org/zap"
+)
+
+const (
+	name      = "status"
+	refreshID = "refresh_status"
+
+	warningLimit = 3
+	none         = "<none>"
+	// maxWarningsLength keeps the warnings within the 1024 characters of a field
+	maxWarningsLength = 1000
+)
+
+// Command for showing the health of the server
+type Command struct {
+	PlayerCounter capability.PlayerCounter
+	// Versioner is used to show the server version if set
+	Versioner capability.Versioner
+	// Health is used to show the state of the server workload if set
+	Health capability.HealthSource
+}
+
+// Name of the Command
+func (c Command) Name() string {
+	return name
+}
+
+// Build the Command for installing
+func (c Command) Build() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        name,
+		Description: "Show the status and health of the server",
+		Options:     []*discordgo.ApplicationCommandOption{},
+	}
+}
+
+// MatchInteraction returns if the Command can handle the interaction
+func (c Command) MatchInteraction(id string) bool {
+	return id == refreshID
+}
+
+// HandleCommand handles the initial event
+func (c Command) HandleCommand(ctx context.Context, session *discordgo.Session, i *discordgo.InteractionCreate) error {
+	return c.refreshStatus(ctx, session, i, discordgo.InteractionResponseChannelMessageWithSource)
+}
+
+const debounceSeconds = 10
+
+// HandleInteractions handles follow-up interactions with the original message
+func (c Command) HandleInteractions(ctx context.Context, session *discordgo.Session, i *discordgo.InteractionCreate) error {
+	debouncer := debounce.InteractionTimestamp(extract.EmbedFieldValue(0, 0), debounceSeconds*time.Second)
+	if shouldDebounce, duration := debouncer(i); shouldDebounce {
+		return responses.NewInteractionEphemeral(session, i, fmt.Sprintf("Please wait at least %.f seconds before retrying.", duration.Seconds()))
+	}
+	return c.refreshStatus(ctx, session, i, discordgo.InteractionResponseUpdateMessage)
+}
+
+func (c Command) refreshStatus(ctx context.Context, session *discordgo.Session, i *discordgo.InteractionCreate, responseType discordgo.InteractionResponseType) error {
+	fields := []*discordgo.MessageEmbedField{
+		{
+			Name:  "Last Refresh",
+			Value: debounce.NewTimestampFor(time.Now()),
+		},
+	}
+	fields = append(fields, c.gameFields(ctx)...)
+	if c.Health != nil {
+		fields = append(fields, c.workloadFields(ctx)...)
+	}
+
+	return session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: responseType,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{
+				{
+					Title:       "Server Status",
+					Description: "Click Refresh to get the current status.",
+					Fields:      fields,
+				},
+			},
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.Button{
+							Emoji: discordgo.ComponentEmoji{
+								Name: "♻️",
+							},
+							Label:    "Refresh",
+							Style:    discordgo.SecondaryButton,
+							CustomID: refreshID,
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+func (c Command) gameFields(ctx context.Context) []*discordgo.MessageEmbedField {
+	reachable := "✅ Yes"
+	players := "-"
+	playerCount, err := c.PlayerCounter.CountPlayers(ctx)
+	if err != nil {
+		log.From(ctx).Info("querying server", zap.Error(err))
+		reachable = fmt.Sprintf("❌ No (%s)", err)
+	} else {
+		players = strconv.Itoa(playerCount)
+	}
+
+	fields := []*discordgo.MessageEmbedField{
+		{Name: "Reachable", Value: reachable, Inline: true},
+		{Name: "Players", Value: players, Inline: true},
+	}
+
+	if c.Versioner != nil && err == nil {
+		version, err := c.Versioner.Version(ctx)
+		if err != nil {
+			log.From(ctx).Info("querying server version", zap.Error(err))
+			version = "unknown"
+		}
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "Version", Value: version, Inline: true})
+	}
+
+	return fields
+}
+
+func (c Command) workloadFields(ctx context.Context) []*discordgo.MessageEmbedField {
+	health, err := c.Health.Health(ctx, warningLimit)
+	if err != nil {
+		log.From(ctx).Error("getting workload health", zap.Error(err))
+		return []*discordgo.MessageEmbedField{
+			{Name: "Workload", Value: fmt.Sprintf("failed to get workload health: %s", err)},
+		}
+	}
+
+	fields := []*discordgo.MessageEmbedField{
+		{Name: "Replicas", Value: fmt.Sprintf("%d/%d ready", health.ReadyReplicas, health.DesiredReplicas), Inline: true},
+	}
+	if len(health.Pod) < 1 {
+		return append(fields, &discordgo.MessageEmbedField{Name: "Pod", Value: none, Inline: true})
+	}
+
+	lastTermination := none
+	if len(health.LastTermination) > 0 {
+		lastTermination = health.LastTermination
+	}
+	warnings := none
+	if len(health.Warnings) > 0 {
+		warnings = fmt.Sprintf("```\n%s\n```", responses.Truncate(strings.Join(health.Warnings, "\n"), maxWarningsLength))
+	}
+	node := none
+	if len(health.Node) > 0 {
+		node = health.Node
+	}
+
+	return append(fields,
+		&discordgo.MessageEmbedField{Name: "Pod", Value: health.Pod, Inline: true},
+		&discordgo.MessageEmbedField{Name: "Phase", Value: string(health.PodPhase), Inline: true},
+		&discordgo.MessageEmbedField{Name: "Restarts", Value: strconv.Itoa(int(health.Restarts)), Inline: true},
+		&discordgo.MessageEmbedField{Name: "Node", Value: node, Inline: true},
+		&discordgo.MessageEmbedField{Name: "Age", Value: time.Since(health.Created).Round(time.Second).String(), Inline: true},
+		&discordgo.MessageEmbedField{Name: "Last Termination", Value: lastTermination},
+		&discordgo.MessageEmbedField{Name: "Recent Warnings", Value: warnings},
+	)
+}
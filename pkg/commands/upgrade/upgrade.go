@@ -0,0 +1,327 @@
+package upgrade
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/playnet-public/mc-bot/pkg/bot/extract"
+	"github.com/playnet-public/mc-bot/pkg/bot/responses"
+	"github.com/playnet-public/mc-bot/pkg/capability"
+	"github.com/playnet-public/mc-bot/pkg/kubernetes"
+	"github.com/seibert-media/golibs/log"
+	"go.uber.org/zap"
+)
+
+const (
+	name      = "upgrade"
+	approveID = "approve_upgrade"
+	denyID    = "deny_upgrade"
+
+	defaultTimeout = 10 * time.Minute
+	pollInterval   = 5 * time.Second
+	eventLimit     = 5
+)
+
+// Command for upgrading the server version with approval and automatic rollback
+type Command struct {
+	ApproverRole string
+
+	Upgrader capability.Upgrader
+	// PlayerCounter is used to check the game endpoint responds after the upgrade
+	PlayerCounter capability.PlayerCounter
+	// Timeout for the upgraded server to become playable before rolling back, capped to responses.MaxTimeout
+	Timeout time.Duration
+}
+
+// Name of the Command
+func (c Command) Name() string {
+	return name
+}
+
+// Build the Command for installing
+func (c Command) Build() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        name,
+		Description: "Show the server version or request an upgrade",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "version",
+				Description: "The version to upgrade to, e.g. 1.20.4",
+				Required:    false,
+			},
+		},
+	}
+}
+
+// MatchInteraction returns if the Command can handle the interaction
+func (c Command) MatchInteraction(id string) bool {
+	return id == approveID || id == denyID
+}
+
+// HandleCommand handles the initial event
+func (c Command) HandleCommand(ctx context.Context, session *discordgo.Session, i *discordgo.InteractionCreate) error {
+	spec, err := c.Upgrader.Spec(ctx)
+	if err != nil {
+		log.From(ctx).Error("getting server spec", zap.Error(err))
+		return responses.NewInteractionError(session, i, fmt.Errorf("failed to get the server version: %w", err))
+	}
+
+	var version string
+	for _, option := range i.ApplicationCommandData().Options {
+		if option.Name == "version" {
+			version = strings.TrimSpace(option.StringValue())
+		}
+	}
+	if len(version) < 1 {
+		return session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Embeds: []*discordgo.MessageEmbed{
+					{
+						Title:       "Server Version",
+						Description: "Use /upgrade with a version to request an upgrade.",
+						Fields:      specFields(spec),
+					},
+				},
+			},
+		})
+	}
+	if err := kubernetes.ValidateVersion(version); err != nil {
+		return responses.NewInteractionEphemeral(session, i, fmt.Sprintf("Invalid version: %s.", err))
+	}
+	if version == spec.Version {
+		return responses.NewInteractionEphemeral(session, i, fmt.Sprintf("The server already runs %s.", version))
+	}
+
+	return session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{
+				{
+					Title:       "Requesting Upgrade",
+					Description: "Please wait for approval. The server restarts during the upgrade and is rolled back if it doesn't come up.",
+					Fields: append([]*discordgo.MessageEmbedField{
+						{
+							Name:  "Requested Version",
+							Value: version,
+						},
+					}, specFields(spec)...),
+				},
+			},
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.Button{
+							Emoji: discordgo.ComponentEmoji{
+								Name: "✅",
+							},
+							Label:    "Approve",
+							Style:    discordgo.SuccessButton,
+							CustomID: approveID,
+						},
+						discordgo.Button{
+							Emoji: discordgo.ComponentEmoji{
+								Name: "✖️",
+							},
+							Label:    "Deny",
+							Style:    discordgo.DangerButton,
+							CustomID: denyID,
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+// HandleInteractions handles follow-up interactions with the original message
+func (c Command) HandleInteractions(ctx context.Context, session *discordgo.Session, i *discordgo.InteractionCreate) error {
+	if !c.isApprover(i.Member) {
+		return c.respondNotApprover(session, i)
+	}
+
+	version, err := extract.EmbedFieldValue(0, 0)(i.Message)
+	if err == nil {
+		err = kubernetes.ValidateVersion(version)
+	}
+	if err != nil {
+		return responses.NewInteractionError(session, i, fmt.Errorf("invalid upgrade message: %w", err))
+	}
+
+	if i.MessageComponentData().CustomID == denyID {
+		return session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseUpdateMessage,
+			Data: &discordgo.InteractionResponseData{
+				Components: []discordgo.MessageComponent{},
+				Embeds: []*discordgo.MessageEmbed{
+					{
+						Title:       "Upgrade denied",
+						Description: fmt.Sprintf("The upgrade to **%s** was denied.", version),
+					},
+				},
+			},
+		})
+	}
+
+	return c.upgrade(ctx, session, i, version)
+}
+
+func (c Command) upgrade(ctx context.Context, session *discordgo.Session, i *discordgo.InteractionCreate, version string) error {
+	replicas, err := c.Upgrader.Replicas(ctx)
+	if err != nil {
+		return responses.NewInteractionError(session, i, fmt.Errorf("failed to get replicas: %w", err))
+	}
+	if replicas < 1 {
+		return responses.NewInteractionEphemeral(session, i, "The server is wound down. Use /wakeup first, so the upgrade can be verified.")
+	}
+
+	started := time.Now()
+	previous, err := c.Upgrader.Upgrade(ctx, version)
+	if errors.Is(err, kubernetes.ErrSameVersion) {
+		return responses.NewInteractionEphemeral(session, i, fmt.Sprintf("The server already runs %s.", version))
+	}
+	if err != nil {
+		log.From(ctx).Error("upgrading server", zap.Error(err))
+		return responses.NewInteractionError(session, i, fmt.Errorf("failed to upgrade the server: %w", err))
+	}
+	log.From(ctx).Info("upgrading server", zap.String("from", previous.Version), zap.String("to", version))
+
+	if err := session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Components: []discordgo.MessageComponent{},
+			Embeds: []*discordgo.MessageEmbed{
+				{
+					Title:       "Upgrading Server",
+					Description: fmt.Sprintf("Upgrading from **%s** to **%s**. Waiting for the server to come up.", previous.Version, version),
+				},
+			},
+		},
+	}); err != nil {
+		return err
+	}
+
+	if c.waitPlayable(ctx, started) {
+		return c.respondDone(session, i, &discordgo.MessageEmbed{
+			Title:       "Upgraded Server",
+			Description: fmt.Sprintf("The server now runs **%s**.", version),
+		})
+	}
+	return c.rollback(session, i, previous, version)
+}
+
+// waitPlayable returns if the server rolled out since started and is playable within the timeout
+func (c Command) waitPlayable(ctx context.Context, started time.Time) bool {
+	ctx, cancel := context.WithTimeout(ctx, responses.Timeout(c.Timeout, defaultTimeout))
+	defer cancel()
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+		}
+
+		rolledOut, err := c.Upgrader.RolledOut(ctx, started)
+		if err != nil {
+			log.From(ctx).Error("tracking upgrade", zap.Error(err))
+			continue
+		}
+		if rolledOut && c.isPlayable(ctx) {
+			return true
+		}
+	}
+}
+
+// isPlayable returns if the game endpoint responds
+func (c Command) isPlayable(ctx context.Context) bool {
+	if c.PlayerCounter == nil {
+		return true
+	}
+	_, err := c.PlayerCounter.CountPlayers(ctx)
+	return err == nil
+}
+
+func (c Command) rollback(session *discordgo.Session, i *discordgo.InteractionCreate, previous capability.ServerSpec, version string) error {
+	// the tracking context is done already, so the rollback uses a fresh one
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	eventsValue := "<none>"
+	events, err := c.Upgrader.Events(ctx, eventLimit)
+	if err != nil {
+		eventsValue = fmt.Sprintf("failed to get events: %s", err)
+	} else if len(events) > 0 {
+		eventsValue = fmt.Sprintf("```\n%s\n```", strings.Join(events, "\n"))
+	}
+
+	description := fmt.Sprintf("The server didn't come up with **%s** in time and was rolled back to **%s**.", version, previous.Version)
+	if err := c.Upgrader.Rollback(ctx, previous); err != nil {
+		log.From(ctx).Error("rolling back upgrade", zap.Error(err))
+		description = fmt.Sprintf("The server didn't come up with **%s** in time and rolling back to **%s** failed: %s. Please check on it.", version, previous.Version, err)
+	}
+
+	return c.respondDone(session, i, &discordgo.MessageEmbed{
+		Title:       "Upgrade failed",
+		Description: description,
+		Fields: []*discordgo.MessageEmbedField{
+			{
+				Name:  "Events",
+				Value: eventsValue,
+			},
+		},
+	})
+}
+
+// respondDone edits the response and mentions the approver in a follow-up, as edits don't notify
+func (c Command) respondDone(session *discordgo.Session, i *discordgo.InteractionCreate, embed *discordgo.MessageEmbed) error {
+	if _, err := session.InteractionResponseEdit(session.State.User.ID, i.Interaction, &discordgo.WebhookEdit{
+		Embeds:     []*discordgo.MessageEmbed{embed},
+		Components: []discordgo.MessageComponent{},
+	}); err != nil {
+		return err
+	}
+
+	content := embed.Title
+	if i.Member != nil {
+		content = fmt.Sprintf("%s, %s", i.Member.Mention(), strings.ToLower(embed.Title))
+	}
+	_, err := session.FollowupMessageCreate(session.State.User.ID, i.Interaction, false, &discordgo.WebhookParams{
+		Content: content,
+	})
+	return err
+}
+
+func specFields(spec capability.ServerSpec) []*discordgo.MessageEmbedField {
+	source := "image tag"
+	if len(spec.VersionEnv) > 0 {
+		source = spec.VersionEnv + " env"
+	}
+	return []*discordgo.MessageEmbedField{
+		{Name: "Current Version", Value: fmt.Sprintf("%s (%s)", spec.Version, source), Inline: true},
+		{Name: "Image", Value: spec.Image, Inline: true},
+	}
+}
+
+func (c Command) isApprover(member *discordgo.Member) bool {
+	if member == nil {
+		return false
+	}
+	for _, role := range member.Roles {
+		if role == c.ApproverRole {
+			return true
+		}
+	}
+	return false
+}
+
+func (c Command) respondNotApprover(session *discordgo.Session, i *discordgo.InteractionCreate) error {
+	return responses.NewInteractionEphemeral(session, i, fmt.Sprintf("Only members with the <@&%s> role can approve upgrades. Please wait :-)", c.ApproverRole))
+}
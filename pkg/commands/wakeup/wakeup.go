@@ -3,22 +3,34 @@ package wakeup
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/playnet-public/mc-bot/pkg/bot/responses"
+	"github.com/playnet-public/mc-bot/pkg/capability"
 	"github.com/seibert-media/golibs/log"
 	"go.uber.org/zap"
 )
 
 const (
 	name = "wakeup"
+
+	defaultTimeout = 10 * time.Minute
+	pollInterval   = 5 * time.Second
+	eventLimit     = 5
 )
 
 // Command for waking up a scaled down server
 type Command struct {
-	Scaler interface {
-		ScaleUp(ctx context.Context) error
-	}
+	Scaler capability.Scaler
+
+	// Tracker follows the server coming up if set
+	Tracker capability.ProgressTracker
+	// PlayerCounter is used to check the game endpoint responds once the server is ready
+	PlayerCounter capability.PlayerCounter
+	// Timeout for the server to become playable, capped to responses.MaxTimeout
+	Timeout time.Duration
 }
 
 // Name of the Command
@@ -59,17 +71,135 @@ func (c Command) wakeupNow(ctx context.Context, session *discordgo.Session, i *d
 		log.From(ctx).Error("scaling up server", zap.Error(err))
 		return responses.NewInteractionError(session, i, fmt.Errorf("failed to scale up the server: %w", err))
 	}
-	return session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+	if err := session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: responseType,
 		Data: &discordgo.InteractionResponseData{
-			Embeds: []*discordgo.MessageEmbed{
-				{
-					Title:       "Waking up Server",
-					Description: "Use /winddown to bring it down.",
-					Fields:      []*discordgo.MessageEmbedField{},
-				},
-			},
+			Embeds:     []*discordgo.MessageEmbed{wakingEmbed(capability.PhaseScaling)},
 			Components: []discordgo.MessageComponent{},
 		},
+	}); err != nil {
+		return err
+	}
+
+	if c.Tracker == nil {
+		return nil
+	}
+	return c.track(ctx, session, i)
+}
+
+// track the server coming up, editing the response on every phase change
+func (c Command) track(ctx context.Context, session *discordgo.Session, i *discordgo.InteractionCreate) error {
+	ctx, cancel := context.WithTimeout(ctx, responses.Timeout(c.Timeout, defaultTimeout))
+	defer cancel()
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	lastPhase := capability.PhaseScaling
+	for {
+		select {
+		case <-ctx.Done():
+			return c.respondFailed(session, i, lastPhase)
+		case <-ticker.C:
+		}
+
+		phase, err := c.Tracker.Phase(ctx)
+		if err != nil {
+			log.From(ctx).Error("tracking wakeup", zap.Error(err))
+			continue
+		}
+		if phase != lastPhase {
+			log.From(ctx).Info("wakeup progressing", zap.String("phase", string(phase)))
+			lastPhase = phase
+			if err := c.edit(session, i, wakingEmbed(phase)); err != nil {
+				log.From(ctx).Error("updating wakeup response", zap.Error(err))
+			}
+		}
+		if phase != capability.PhaseReady || !c.isPlayable(ctx) {
+			continue
+		}
+
+		return c.respondReady(session, i)
+	}
+}
+
+// isPlayable returns if the game endpoint responds
+func (c Command) isPlayable(ctx context.Context) bool {
+	if c.PlayerCounter == nil {
+		return true
+	}
+	_, err := c.PlayerCounter.CountPlayers(ctx)
+	return err == nil
+}
+
+func (c Command) respondReady(session *discordgo.Session, i *discordgo.InteractionCreate) error {
+	if err := c.edit(session, i, &discordgo.MessageEmbed{
+		Title:       "Server is up",
+		Description: "The server is ready to play. Use /winddown to bring it down.",
+		Fields:      []*discordgo.MessageEmbedField{},
+	}); err != nil {
+		return err
+	}
+
+	// edits don't notify, so the requester gets mentioned in a follow-up
+	mention := "The server"
+	if i.Member != nil {
+		mention = i.Member.Mention() + ", the server"
+	} else if i.User != nil {
+		mention = i.User.Mention() + ", the server"
+	}
+	_, err := session.FollowupMessageCreate(session.State.User.ID, i.Interaction, false, &discordgo.WebhookParams{
+		Content: mention + " is ready to play!",
 	})
+	return err
+}
+
+func (c Command) respondFailed(session *discordgo.Session, i *discordgo.InteractionCreate, lastPhase capability.Phase) error {
+	// the tracking context is done already, so events are fetched with a fresh one
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	eventsValue := "<none>"
+	events, err := c.Tracker.Events(ctx, eventLimit)
+	if err != nil {
+		eventsValue = fmt.Sprintf("failed to get events: %s", err)
+	} else if len(events) > 0 {
+		eventsValue = fmt.Sprintf("```\n%s\n```", strings.Join(events, "\n"))
+	}
+
+	return c.edit(session, i, &discordgo.MessageEmbed{
+		Title:       "Wakeup failed",
+		Description: "The server did not come up in time. Please check on it.",
+		Fields: []*discordgo.MessageEmbedField{
+			{
+				Name:  "Last Phase",
+				Value: string(lastPhase),
+			},
+			{
+				Name:  "Events",
+				Value: eventsValue,
+			},
+		},
+	})
+}
+
+func (c Command) edit(session *discordgo.Session, i *discordgo.InteractionCreate, embed *discordgo.MessageEmbed) error {
+	_, err := session.InteractionResponseEdit(session.State.User.ID, i.Interaction, &discordgo.WebhookEdit{
+		Embeds:     []*discordgo.MessageEmbed{embed},
+		Components: []discordgo.MessageComponent{},
+	})
+	return err
+}
+
+func wakingEmbed(phase capability.Phase) *discordgo.MessageEmbed {
+	return &discordgo.MessageEmbed{
+		Title:       "Waking up Server",
+		Description: "Use /winddown to bring it down.",
+		Fields: []*discordgo.MessageEmbedField{
+			{
+				Name:  "Phase",
+				Value: string(phase),
+			},
+		},
+	}
 }
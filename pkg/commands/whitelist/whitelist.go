@@ -7,6 +7,7 @@ import (
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/playnet-public/mc-bot/pkg/bot/responses"
+	"github.com/playnet-public/mc-bot/pkg/capability"
 )
 
 const (
@@ -18,9 +19,7 @@ const (
 type Command struct {
 	ApproverRole string
 
-	Whitelister interface {
-		Whitelist(ctx context.Context, username string) error
-	}
+	Whitelister capability.Whitelister
 }
 
 // Name of the Command
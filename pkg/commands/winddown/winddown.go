@@ -10,6 +10,7 @@ import (
 	"github.com/playnet-public/mc-bot/pkg/bot/debounce"
 	"github.com/playnet-public/mc-bot/pkg/bot/extract"
 	"github.com/playnet-public/mc-bot/pkg/bot/responses"
+	"github.com/playnet-public/mc-bot/pkg/capability"
 	"github.com/seibert-media/golibs/log"
 	"go.uber.org/zap"
 )
@@ -25,15 +26,10 @@ const (
 type Command struct {
 	OverriderRole string
 
-	PlayerCounter interface {
-		CountPlayers(ctx context.Context) (int, error)
-	}
-	Scaler interface {
-		ScaleDown(ctx context.Context) error
-	}
-	MessageSender interface {
-		SendMessage(ctx context.Context, msg string) error
-	}
+	PlayerCounter capability.PlayerCounter
+	Scaler        capability.Scaler
+	// MessageSender announces the wind down to the players if set
+	MessageSender capability.MessageSender
 }
 
 // Name of the Command
@@ -59,14 +55,16 @@ func (c Command) MatchInteraction(id string) bool {
 
 // HandleCommand handles the initial event
 func (c Command) HandleCommand(ctx context.Context, session *discordgo.Session, i *discordgo.InteractionCreate) error {
-	var mention string
-	if i.Member != nil && i.Member.User != nil {
-		mention = i.Member.User.String()
-	} else if i.User != nil {
-		mention = i.User.String()
-	}
-	if err := c.MessageSender.SendMessage(ctx, fmt.Sprintf("%s is requesting a server wind down. You can leave the server to comply with their request.", mention)); err != nil {
-		log.From(ctx).Error("sending winddown message", zap.Error(err))
+	if c.MessageSender != nil {
+		var mention string
+		if i.Member != nil && i.Member.User != nil {
+			mention = i.Member.User.String()
+		} else if i.User != nil {
+			mention = i.User.String()
+		}
+		if err := c.MessageSender.SendMessage(ctx, fmt.Sprintf("%s is requesting a server wind down. You can leave the server to comply with their request.", mention)); err != nil {
+			log.From(ctx).Error("sending winddown message", zap.Error(err))
+		}
 	}
 	return c.tryWindDown(ctx, session, i, discordgo.InteractionResponseChannelMessageWithSource)
 }
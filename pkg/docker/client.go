@@ -0,0 +1,132 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// DefaultSocket of the Docker Engine
+const DefaultSocket = "/var/run/docker.sock"
+
+// apiVersion used for requests, supported by Docker Engine 1.13 and later
+const apiVersion = "v1.25"
+
+// Client for the Docker Engine API managing a single container
+type Client struct {
+	container   string
+	stopTimeout time.Duration
+	httpClient  *http.Client
+}
+
+// NewClient for the container talking to the Docker Engine listening on socket.
+// stopTimeout is the time given to the container to stop before it gets killed
+func NewClient(socket string, container string, stopTimeout time.Duration) Client {
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	return Client{
+		container:   container,
+		stopTimeout: stopTimeout,
+		httpClient: &http.Client{
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					return dialer.DialContext(ctx, "unix", socket)
+				},
+			},
+		},
+	}
+}
+
+// Restart the container
+func (c Client) Restart(ctx context.Context) error {
+	return c.post(ctx, "restart", c.stopParams())
+}
+
+// ScaleUp starts the container
+func (c Client) ScaleUp(ctx context.Context) error {
+	return c.post(ctx, "start", nil)
+}
+
+// ScaleDown stops the container
+func (c Client) ScaleDown(ctx context.Context) error {
+	return c.post(ctx, "stop", c.stopParams())
+}
+
+// Replicas returns 1 if the container is running and 0 otherwise
+func (c Client) Replicas(ctx context.Context) (int32, error) {
+	resp, err := c.do(ctx, http.MethodGet, "json", nil)
+	if err != nil {
+		return -1, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return -1, errorFrom(resp)
+	}
+
+	var inspect struct {
+		State struct {
+			Running bool `json:"Running"`
+		} `json:"State"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&inspect); err != nil {
+		return -1, fmt.Errorf("decoding container state: %w", err)
+	}
+	if inspect.State.Running {
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func (c Client) stopParams() url.Values {
+	return url.Values{"t": []string{strconv.Itoa(int(c.stopTimeout.Seconds()))}}
+}
+
+// post an action for the container, treating 304 (already started or stopped) as success
+func (c Client) post(ctx context.Context, action string, params url.Values) error {
+	resp, err := c.do(ctx, http.MethodPost, action, params)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNoContent, http.StatusNotModified:
+		return nil
+	default:
+		return errorFrom(resp)
+	}
+}
+
+func (c Client) do(ctx context.Context, method string, action string, params url.Values) (*http.Response, error) {
+	u := url.URL{
+		Scheme:   "http",
+		Host:     "docker",
+		Path:     fmt.Sprintf("/%s/containers/%s/%s", apiVersion, url.PathEscape(c.container), action),
+		RawQuery: params.Encode(),
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.httpClient.Do(req)
+}
+
+// errorFrom builds an error from the message of a failed response
+func errorFrom(resp *http.Response) error {
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+	if err != nil {
+		return fmt.Errorf("docker responded with %s", resp.Status)
+	}
+	var msg struct {
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &msg); err != nil || len(msg.Message) < 1 {
+		return fmt.Errorf("docker responded with %s", resp.Status)
+	}
+	return fmt.Errorf("docker responded with %s: %s", resp.Status, msg.Message)
+}
@@ -0,0 +1,119 @@
+package docker
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type request struct {
+	method string
+	path   string
+	query  string
+}
+
+// newEngine serves handler on a unix socket, returning the socket and the received requests
+func newEngine(t *testing.T, handler http.HandlerFunc) (string, *[]request) {
+	socket := filepath.Join(t.TempDir(), "docker.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	requests := &[]request{}
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests = append(*requests, request{method: r.Method, path: r.URL.Path, query: r.URL.RawQuery})
+		handler(w, r)
+	}))
+	server.Listener = listener
+	server.Start()
+	t.Cleanup(server.Close)
+	return socket, requests
+}
+
+func TestClientActions(t *testing.T) {
+	cases := []struct {
+		name   string
+		action func(c Client) error
+		path   string
+		query  string
+	}{
+		{name: "restart", action: func(c Client) error { return c.Restart(context.Background()) }, path: "/v1.25/containers/mc/restart", query: "t=45"},
+		{name: "start", action: func(c Client) error { return c.ScaleUp(context.Background()) }, path: "/v1.25/containers/mc/start"},
+		{name: "stop", action: func(c Client) error { return c.ScaleDown(context.Background()) }, path: "/v1.25/containers/mc/stop", query: "t=45"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			socket, requests := newEngine(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			})
+
+			if err := c.action(NewClient(socket, "mc", 45*time.Second)); err != nil {
+				t.Fatal(err)
+			}
+			if len(*requests) != 1 {
+				t.Fatalf("expected one request, got %d", len(*requests))
+			}
+			received := (*requests)[0]
+			if received.method != http.MethodPost || received.path != c.path || received.query != c.query {
+				t.Errorf("expected POST %s?%s, got %s %s?%s", c.path, c.query, received.method, received.path, received.query)
+			}
+		})
+	}
+}
+
+func TestClientNotModified(t *testing.T) {
+	socket, _ := newEngine(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotModified)
+	})
+
+	if err := NewClient(socket, "mc", time.Second).ScaleUp(context.Background()); err != nil {
+		t.Errorf("expected an already started container to be fine, got %v", err)
+	}
+}
+
+func TestClientError(t *testing.T) {
+	socket, _ := newEngine(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message":"No such container: mc"}`))
+	})
+
+	err := NewClient(socket, "mc", time.Second).ScaleDown(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "No such container: mc") {
+		t.Errorf("expected the engine's message in the error, got %v", err)
+	}
+}
+
+func TestClientReplicas(t *testing.T) {
+	for _, running := range []bool{true, false} {
+		socket, requests := newEngine(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if running {
+				_, _ = w.Write([]byte(`{"State":{"Running":true}}`))
+			} else {
+				_, _ = w.Write([]byte(`{"State":{"Running":false}}`))
+			}
+		})
+
+		replicas, err := NewClient(socket, "mc", time.Second).Replicas(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := int32(0)
+		if running {
+			expected = 1
+		}
+		if replicas != expected {
+			t.Errorf("expected %d replicas for running %t, got %d", expected, running, replicas)
+		}
+		if received := (*requests)[0]; received.method != http.MethodGet || received.path != "/v1.25/containers/mc/json" {
+			t.Errorf("expected container inspection, got %s %s", received.method, received.path)
+		}
+	}
+}
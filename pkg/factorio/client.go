@@ -0,0 +1,117 @@
+package factorio
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/playnet-public/mc-bot/pkg/capability"
+	"github.com/playnet-public/mc-bot/pkg/minecraft"
+	"github.com/seibert-media/golibs/log"
+	"go.uber.org/zap"
+)
+
+// Client wraps a RCON connection to a Factorio server exposing required features
+type Client struct {
+	rcon minecraft.CommandSender
+}
+
+// NewClient with default settings
+func NewClient() Client {
+	return Client{}
+}
+
+// Setup brings the Client into a functional state by starting a RCON session
+// with the provided credentials
+func (c Client) Setup(address string, password string) (Client, error) {
+	rcon := minecraft.NewReconnectingRCON(address, password)
+
+	c.rcon = rcon
+	if err := rcon.Setup(); err != nil {
+		return c, err
+	}
+
+	return c, nil
+}
+
+// SendCommand to the server via RCON
+func (c Client) SendCommand(ctx context.Context, command string) (capability.Response, error) {
+	msg, err := c.rcon.SendCommand(ctx, command)
+	if err != nil {
+		return capability.Response{}, err
+	}
+
+	log.From(ctx).Info("receiving sendCommand response", zap.String("payload", msg.Body))
+
+	return msg, nil
+}
+
+// SendMessage to the server chat. Factorio posts any RCON input not starting
+// with a slash as chat message, so leading slashes are stripped
+func (c Client) SendMessage(ctx context.Context, msg string) error {
+	msg = strings.TrimLeft(strings.ReplaceAll(msg, "\n", " "), "/")
+	_, err := c.SendCommand(ctx, msg)
+	return err
+}
+
+// Whitelist the provided username
+func (c Client) Whitelist(ctx context.Context, username string) error {
+	_, err := c.SendCommand(ctx, "/whitelist add "+username)
+	return err
+}
+
+// Save the map on the server
+func (c Client) Save(ctx context.Context) error {
+	_, err := c.SendCommand(ctx, "/server-save")
+	return err
+}
+
+var (
+	onlineCountRegex  = regexp.MustCompile(`Online players \((\d+)\)`)
+	onlinePlayerRegex = regexp.MustCompile(`(?m)^\s*(\S+) \(online\)`)
+)
+
+// CountPlayers returns the number of players online
+func (c Client) CountPlayers(ctx context.Context) (int, error) {
+	playerCount, _, err := c.Players(ctx)
+	return playerCount, err
+}
+
+// Players returns the number of players online and their names
+func (c Client) Players(ctx context.Context) (int, []string, error) {
+	msg, err := c.SendCommand(ctx, "/players online")
+	if err != nil {
+		return -1, nil, err
+	}
+
+	count := onlineCountRegex.FindStringSubmatch(msg.Body)
+	if count == nil {
+		return -1, nil, fmt.Errorf("invalid player list response: %s", msg.Body)
+	}
+	playerCount, err := strconv.Atoi(count[1])
+	if err != nil {
+		return -1, nil, fmt.Errorf("invalid player count %s: %w", count[1], err)
+	}
+
+	players := make([]string, 0, playerCount)
+	for _, match := range onlinePlayerRegex.FindAllStringSubmatch(msg.Body, -1) {
+		players = append(players, match[1])
+	}
+	return playerCount, players, nil
+}
+
+// Evolution returns the evolution factor of the enemies as reported by the server,
+// which doesn't disable achievements unlike commands running Lua
+func (c Client) Evolution(ctx context.Context) (string, error) {
+	msg, err := c.SendCommand(ctx, "/evolution")
+	if err != nil {
+		return "", err
+	}
+	evolution := strings.TrimSpace(msg.Body)
+	if len(evolution) < 1 {
+		return "", fmt.Errorf("empty evolution response")
+	}
+	return evolution, nil
+}
@@ -0,0 +1,261 @@
+package game
+
+import (
+	"context"
+	"time"
+
+	"github.com/playnet-public/mc-bot/pkg/bot"
+	"github.com/playnet-public/mc-bot/pkg/capability"
+	"github.com/playnet-public/mc-bot/pkg/commands/keepawake"
+	"github.com/playnet-public/mc-bot/pkg/commands/kick"
+	"github.com/playnet-public/mc-bot/pkg/commands/logs"
+	"github.com/playnet-public/mc-bot/pkg/commands/players"
+	"github.com/playnet-public/mc-bot/pkg/commands/resources"
+	"github.com/playnet-public/mc-bot/pkg/commands/restart"
+	"github.com/playnet-public/mc-bot/pkg/commands/serverinfo"
+	"github.com/playnet-public/mc-bot/pkg/commands/status"
+	"github.com/playnet-public/mc-bot/pkg/commands/upgrade"
+	"github.com/playnet-public/mc-bot/pkg/commands/wakeup"
+	"github.com/playnet-public/mc-bot/pkg/commands/whitelist"
+	"github.com/playnet-public/mc-bot/pkg/commands/winddown"
+	"github.com/playnet-public/mc-bot/pkg/idle"
+	"github.com/playnet-public/mc-bot/pkg/operands/rcon"
+	"github.com/seibert-media/golibs/log"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultTimeout      = 10 * time.Minute
+	playersPollInterval = 10 * time.Second
+)
+
+// Server of a game managed by the bot. Its commands are installed depending on the
+// capabilities of its Client and Backend
+type Server struct {
+	// Name of the game, e.g. valheim
+	Name          string
+	ApproverRole  string
+	RCONChannelID string
+
+	// Client talking to the game server
+	Client capability.PlayerCounter
+	// Backend managing the server process if set, e.g. a Kubernetes workload
+	Backend interface{}
+
+	// Restarter overrides how the server restarts, the Backend or the Client are used if unset
+	Restarter capability.Restarter
+	// Versioner overrides how the version of the server is reported, the Client is used if unset
+	Versioner capability.Versioner
+	// Vote enables community votes to restart if set
+	Vote *restart.Vote
+	// ResourceAlert watches the memory usage if set, its Usage is set by the Registry
+	ResourceAlert *resources.Alert
+	// IdleWatcher winds down the server once it's empty for a while if set and the Backend can scale.
+	// Its PlayerCounter, Scaler and Hold are set by the Registry
+	IdleWatcher *idle.Watcher
+	// Timeout for the server to become playable after wakeups and upgrades
+	Timeout time.Duration
+}
+
+// Registry of the game servers managed by the bot
+type Registry struct {
+	servers []Server
+}
+
+// NewRegistry without any servers
+func NewRegistry() Registry {
+	return Registry{}
+}
+
+// Register server with the Registry
+func (r Registry) Register(server Server) Registry {
+	r.servers = append(r.servers, server)
+	return r
+}
+
+// Install the commands supported by the registered servers into b
+func (r Registry) Install(ctx context.Context, b bot.Service) bot.Service {
+	for _, server := range r.servers {
+		b = server.install(ctx, b)
+	}
+	return b
+}
+
+func (s Server) install(ctx context.Context, b bot.Service) bot.Service {
+	log.From(ctx).Info("installing game server", zap.String("game", s.Name), zap.Strings("capabilities", s.capabilities()))
+
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	var messageSender capability.MessageSender
+	if capability.Supports(s.Client, capability.Messages) {
+		messageSender = s.Client.(capability.MessageSender)
+	}
+
+	if capability.Supports(s.Client, capability.PlayerList) {
+		playersCommand := players.Command{
+			PlayerLister: s.Client.(capability.PlayerLister),
+			PollInterval: playersPollInterval,
+		}
+		if capability.Supports(s.Client, capability.PlayerDetails) {
+			playersCommand.Details = s.Client.(capability.PlayerDetailLister)
+		}
+		b = b.WithCommand(playersCommand)
+	}
+	if capability.Supports(s.Client, capability.Whitelist) {
+		b = b.WithCommand(whitelist.Command{
+			ApproverRole: s.ApproverRole,
+			Whitelister:  s.Client.(capability.Whitelister),
+		})
+	}
+	if capability.Supports(s.Client, capability.Kick) {
+		b = b.WithCommand(kick.Command{
+			ApproverRole: s.ApproverRole,
+			Kicker:       s.Client.(capability.Kicker),
+		})
+	}
+	if capability.Supports(s.Client, capability.Commands) && len(s.RCONChannelID) > 0 {
+		b = b.WithOperand(rcon.Operand{
+			ChannelID:     s.RCONChannelID,
+			RCONRole:      s.ApproverRole,
+			CommandSender: s.Client.(capability.CommandSender),
+		})
+	}
+	if capability.Supports(s.Client, capability.ServerInfo) {
+		serverinfoCommand := serverinfo.Command{
+			Querier: s.Client.(capability.Querier),
+		}
+		if capability.Supports(s.Client, capability.Rules) {
+			serverinfoCommand.RulesLister = s.Client.(capability.RulesLister)
+		}
+		b = b.WithCommand(serverinfoCommand)
+	}
+
+	statusCommand := status.Command{
+		PlayerCounter: s.Client,
+		Versioner:     s.Versioner,
+	}
+	if s.Versioner == nil && capability.Supports(s.Client, capability.Version) {
+		statusCommand.Versioner = s.Client.(capability.Versioner)
+	}
+	if capability.Supports(s.Backend, capability.Health) {
+		statusCommand.Health = s.Backend.(capability.HealthSource)
+	}
+	b = b.WithCommand(statusCommand)
+
+	if capability.Supports(s.Backend, capability.Scale) {
+		scaler := s.Backend.(capability.Scaler)
+		b = b.WithCommand(winddown.Command{
+			OverriderRole: s.ApproverRole,
+			PlayerCounter: s.Client,
+			Scaler:        scaler,
+			MessageSender: messageSender,
+		})
+		wakeupCommand := wakeup.Command{
+			Scaler:        scaler,
+			PlayerCounter: s.Client,
+			Timeout:       timeout,
+		}
+		if capability.Supports(s.Backend, capability.Progress) {
+			wakeupCommand.Tracker = s.Backend.(capability.ProgressTracker)
+		}
+		b = b.WithCommand(wakeupCommand)
+
+		if s.IdleWatcher != nil {
+			watcher := *s.IdleWatcher
+			watcher.PlayerCounter = s.Client
+			watcher.Scaler = scaler
+			watcher.Hold = idle.NewHold()
+			b = b.WithRunner(watcher)
+			b = b.WithCommand(keepawake.Command{
+				ApproverRole: s.ApproverRole,
+				Hold:         watcher.Hold,
+			})
+		}
+	}
+	if capability.Supports(s.Backend, capability.Logs) {
+		b = b.WithCommand(logs.Command{
+			ApproverRole: s.ApproverRole,
+			Logger:       s.Backend.(capability.LogSource),
+			Snapshots:    &logs.Snapshots{},
+		})
+	}
+	if capability.Supports(s.Backend, capability.Usage) {
+		usage := s.Backend.(capability.UsageSource)
+		b = b.WithCommand(resources.Command{
+			Usage: usage,
+		})
+		if s.ResourceAlert != nil {
+			alert := *s.ResourceAlert
+			alert.Usage = usage
+			b = b.WithRunner(alert)
+		}
+	}
+	if capability.Supports(s.Backend, capability.Upgrade) {
+		b = b.WithCommand(upgrade.Command{
+			ApproverRole:  s.ApproverRole,
+			Upgrader:      s.Backend.(capability.Upgrader),
+			PlayerCounter: s.Client,
+			Timeout:       timeout,
+		})
+	}
+
+	if restarter := s.restarter(); restarter != nil {
+		restartCommand := restart.Command{
+			OverriderRole: s.ApproverRole,
+			PlayerCounter: s.Client,
+			Restarter:     restarter,
+			MessageSender: messageSender,
+			Vote:          s.Vote,
+		}
+		if capability.Supports(restarter, capability.RestartTracking) {
+			restartCommand.Tracker = restarter.(capability.RestartTracker)
+		}
+		b = b.WithCommand(restartCommand)
+	}
+
+	return b
+}
+
+// restarter of the server or nil if it can't be restarted
+func (s Server) restarter() capability.Restarter {
+	if s.Restarter != nil {
+		return s.Restarter
+	}
+	if capability.Supports(s.Backend, capability.Restart) {
+		return s.Backend.(capability.Restarter)
+	}
+	if capability.Supports(s.Client, capability.Restart) {
+		return s.Client.(capability.Restarter)
+	}
+	return nil
+}
+
+// capabilities of the client and backend for logging
+func (s Server) capabilities() []string {
+	capabilities := []string{}
+	for _, c := range []capability.Capability{
+		capability.PlayerList, capability.PlayerDetails, capability.Messages, capability.Commands,
+		capability.Whitelist, capability.Kick, capability.Version, capability.ServerInfo,
+		capability.Rules,
+	} {
+		if capability.Supports(s.Client, c) {
+			capabilities = append(capabilities, string(c))
+		}
+	}
+	for _, c := range []capability.Capability{
+		capability.Scale, capability.Progress, capability.Health, capability.Usage, capability.Upgrade, capability.Logs,
+	} {
+		if capability.Supports(s.Backend, c) {
+			capabilities = append(capabilities, string(c))
+		}
+	}
+	if restarter := s.restarter(); restarter != nil {
+		capabilities = append(capabilities, string(capability.Restart))
+		if capability.Supports(restarter, capability.RestartTracking) {
+			capabilities = append(capabilities, string(capability.RestartTracking))
+		}
+	}
+	return capabilities
+}
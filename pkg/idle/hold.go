@@ -0,0 +1,44 @@
+package idle
+
+import (
+	"sync"
+	"time"
+)
+
+// Hold keeps a server awake until it expires or gets released
+type Hold struct {
+	l     sync.Mutex
+	until time.Time
+	by    string
+}
+
+// NewHold without an active hold
+func NewHold() *Hold {
+	return &Hold{}
+}
+
+// Set the hold until the provided time on behalf of by
+func (h *Hold) Set(until time.Time, by string) {
+	h.l.Lock()
+	defer h.l.Unlock()
+	h.until = until
+	h.by = by
+}
+
+// Release the hold
+func (h *Hold) Release() {
+	h.l.Lock()
+	defer h.l.Unlock()
+	h.until = time.Time{}
+	h.by = ""
+}
+
+// Active returns if the hold is active at now, until when and who set it
+func (h *Hold) Active(now time.Time) (bool, time.Time, string) {
+	h.l.Lock()
+	defer h.l.Unlock()
+	if !now.Before(h.until) {
+		return false, time.Time{}, ""
+	}
+	return true, h.until, h.by
+}
@@ -0,0 +1,100 @@
+package idle
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/seibert-media/golibs/log"
+	"go.uber.org/zap"
+)
+
+const name = "idle"
+
+// Watcher winds down a server after it has been empty for a while
+type Watcher struct {
+	// ChannelID to announce idle winddowns in. Announcements are skipped if empty
+	ChannelID string
+	// Interval between player count samples
+	Interval time.Duration
+	// Timeout the server has to be empty for before winding it down
+	Timeout time.Duration
+
+	PlayerCounter interface {
+		CountPlayers(ctx context.Context) (int, error)
+	}
+	Scaler interface {
+		ScaleDown(ctx context.Context) error
+	}
+	// Hold prevents the winddown while active if set
+	Hold *Hold
+}
+
+// Name of the Runner
+func (w Watcher) Name() string {
+	return name
+}
+
+// Run the watcher until ctx is done
+func (w Watcher) Run(ctx context.Context, session *discordgo.Session) error {
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	var emptySince time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case now := <-ticker.C:
+			emptySince = w.sample(ctx, session, now, emptySince)
+		}
+	}
+}
+
+// sample the player count at now and return when the server became empty
+func (w Watcher) sample(ctx context.Context, session *discordgo.Session, now time.Time, emptySince time.Time) time.Time {
+	playerCount, err := w.PlayerCounter.CountPlayers(ctx)
+	if err != nil {
+		// the server is most likely down already, so the idle time starts over once it's back
+		log.From(ctx).Debug("sampling player count", zap.Error(err))
+		return time.Time{}
+	}
+	if playerCount > 0 {
+		return time.Time{}
+	}
+	if emptySince.IsZero() {
+		return now
+	}
+	if now.Sub(emptySince) < w.Timeout {
+		return emptySince
+	}
+
+	if w.Hold != nil {
+		if active, until, by := w.Hold.Active(now); active {
+			log.From(ctx).Debug("skipping idle winddown", zap.Time("until", until), zap.String("by", by))
+			return emptySince
+		}
+	}
+
+	log.From(ctx).Info("winding down idle server", zap.Duration("idle", now.Sub(emptySince)))
+	if err := w.Scaler.ScaleDown(ctx); err != nil {
+		log.From(ctx).Error("scaling down idle server", zap.Error(err))
+		return emptySince
+	}
+	w.announce(ctx, session, now.Sub(emptySince))
+
+	return time.Time{}
+}
+
+func (w Watcher) announce(ctx context.Context, session *discordgo.Session, idle time.Duration) {
+	if len(w.ChannelID) < 1 {
+		return
+	}
+	if _, err := session.ChannelMessageSendEmbed(w.ChannelID, &discordgo.MessageEmbed{
+		Title:       "Winding down Server",
+		Description: fmt.Sprintf("The server has been empty for %s. Use /wakeup to bring it back.", idle.Round(time.Minute)),
+	}); err != nil {
+		log.From(ctx).Error("announcing idle winddown", zap.Error(err))
+	}
+}
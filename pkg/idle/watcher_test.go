@@ -0,0 +1,116 @@
+package idle
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeServer struct {
+	players  int
+	countErr error
+	scaleErr error
+	scales   int
+}
+
+func (s *fakeServer) CountPlayers(ctx context.Context) (int, error) {
+	return s.players, s.countErr
+}
+
+func (s *fakeServer) ScaleDown(ctx context.Context) error {
+	s.scales++
+	return s.scaleErr
+}
+
+func TestWatcherSample(t *testing.T) {
+	now := time.Date(2024, 3, 1, 20, 0, 0, 0, time.UTC)
+	timeout := 15 * time.Minute
+
+	tests := []struct {
+		name       string
+		server     fakeServer
+		hold       time.Time
+		emptySince time.Time
+		expected   time.Time
+		scales     int
+	}{
+		{
+			name:     "empty starts countdown",
+			expected: now,
+		},
+		{
+			name:       "join resets countdown",
+			server:     fakeServer{players: 1},
+			emptySince: now.Add(-10 * time.Minute),
+		},
+		{
+			name:       "uncountable resets countdown",
+			server:     fakeServer{countErr: errors.New("connection refused")},
+			emptySince: now.Add(-10 * time.Minute),
+		},
+		{
+			name:       "empty keeps countdown",
+			emptySince: now.Add(-10 * time.Minute),
+			expected:   now.Add(-10 * time.Minute),
+		},
+		{
+			name:       "timeout scales down",
+			emptySince: now.Add(-timeout),
+			scales:     1,
+		},
+		{
+			name:       "active hold keeps server",
+			hold:       now.Add(time.Hour),
+			emptySince: now.Add(-timeout),
+			expected:   now.Add(-timeout),
+		},
+		{
+			name:       "expired hold scales down",
+			hold:       now,
+			emptySince: now.Add(-timeout),
+			scales:     1,
+		},
+		{
+			name:       "failed scale down retries",
+			server:     fakeServer{scaleErr: errors.New("forbidden")},
+			emptySince: now.Add(-timeout),
+			expected:   now.Add(-timeout),
+			scales:     1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hold := NewHold()
+			hold.Set(tt.hold, "approver")
+			w := Watcher{Timeout: timeout, PlayerCounter: &tt.server, Scaler: &tt.server, Hold: hold}
+
+			emptySince := w.sample(context.Background(), nil, now, tt.emptySince)
+			if !emptySince.Equal(tt.expected) {
+				t.Errorf("expected empty since %s, got %s", tt.expected, emptySince)
+			}
+			if tt.server.scales != tt.scales {
+				t.Errorf("expected %d scale downs, got %d", tt.scales, tt.server.scales)
+			}
+		})
+	}
+}
+
+func TestWatcherScalesDownOnce(t *testing.T) {
+	server := &fakeServer{}
+	w := Watcher{Interval: time.Minute, Timeout: 5 * time.Minute, PlayerCounter: server, Scaler: server}
+
+	now := time.Date(2024, 3, 1, 20, 0, 0, 0, time.UTC)
+	var emptySince time.Time
+	for i := 0; i < 8; i++ {
+		emptySince = w.sample(context.Background(), nil, now, emptySince)
+		now = now.Add(w.Interval)
+		// the server can't be reached once it's wound down
+		if server.scales > 0 {
+			server.countErr = errors.New("connection refused")
+		}
+	}
+	if server.scales != 1 {
+		t.Errorf("expected the server to be scaled down once, got %d", server.scales)
+	}
+}
@@ -5,96 +5,153 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/playnet-public/mc-bot/pkg/capability"
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/selection"
 	"k8s.io/apimachinery/pkg/types"
-	applyconfigurationsautoscalingv1 "k8s.io/client-go/applyconfigurations/autoscaling/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
-// StatefulSetRestarter allows to restart by patching a statefulset
-type StatefulSetRestarter struct {
-	Namespace    string
-	Name         string
-	ClientSet    *kubernetes.Clientset
-	FieldManager string
+// PodRestarter allows to restart by deleting pods matching a certain label
+type PodRestarter struct {
+	Namespace  string
+	LabelKey   string
+	LabelValue string
+	ClientSet  kubernetes.Interface
+	// GracePeriod gives the server time to shut down before it's killed, the default of the pod is used if unset
+	GracePeriod time.Duration
+	// Timeout for the replacement to become ready when tracking restarts
+	Timeout time.Duration
 }
 
-// Restart patches the statefulset to cause a restart
-func (r StatefulSetRestarter) Restart(ctx context.Context) error {
-	// .Spec.Template.ObjectMeta.Annotations["restarter.play-net.org/restartedAt"]
-	patch := []byte(fmt.Sprintf(`[{"op": "add", "path": "/spec/template/metadata/annotations/restarter.play-net.org~1restartedAt", "value": %s}]`, time.Now().UTC().Format(time.RFC3339)))
-	if _, err := r.ClientSet.AppsV1().StatefulSets(r.Namespace).Patch(context.TODO(), r.Name, types.JSONPatchType, patch, v1.PatchOptions{
-		FieldManager: r.FieldManager,
-	}); err != nil {
+const (
+	defaultRestartTimeout = 10 * time.Minute
+	restartPollInterval   = 2 * time.Second
+	restartEventLimit     = 5
+)
+
+// Restart the app by deleting its pod
+func (r PodRestarter) Restart(ctx context.Context) error {
+	selector, err := r.selector()
+	if err != nil {
 		return err
 	}
-	return nil
+	return r.delete(ctx, selector)
 }
 
-// StatefulSetScaler allows to scale down a statefulset
-type StatefulSetScaler struct {
-	Namespace    string
-	Name         string
-	ClientSet    *kubernetes.Clientset
-	FieldManager string
-}
-
-// ScaleDown the statefulset
-func (r StatefulSetScaler) ScaleDown(ctx context.Context) error {
-	if _, err := r.ClientSet.AppsV1().StatefulSets(r.Namespace).ApplyScale(context.TODO(), r.Name,
-		applyconfigurationsautoscalingv1.Scale().
-			WithAPIVersion("autoscaling/v1").
-			WithKind("Scale").
-			WithName(r.Name).
-			WithNamespace(r.Namespace).
-			WithSpec(applyconfigurationsautoscalingv1.ScaleSpec().
-				WithReplicas(0)),
-		v1.ApplyOptions{
-			FieldManager: r.FieldManager,
-			Force:        true,
-		}); err != nil {
+// RestartTracked restarts the app by deleting its pods, waiting for them to terminate and
+// their replacement to become ready. progress is called on every phase change
+func (r PodRestarter) RestartTracked(ctx context.Context, progress func(phase capability.Phase)) error {
+	selector, err := r.selector()
+	if err != nil {
 		return err
 	}
-	return nil
-}
+	pods, err := r.ClientSet.CoreV1().Pods(r.Namespace).List(ctx, v1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		return err
+	}
+	if len(pods.Items) < 1 {
+		return fmt.Errorf("no pods match %s", selector)
+	}
+	replaced := make(map[types.UID]bool, len(pods.Items))
+	for _, pod := range pods.Items {
+		replaced[pod.UID] = true
+	}
 
-// ScaleUp the statefulset
-func (r StatefulSetScaler) ScaleUp(ctx context.Context) error {
-	if _, err := r.ClientSet.AppsV1().StatefulSets(r.Namespace).ApplyScale(context.TODO(), r.Name,
-		applyconfigurationsautoscalingv1.Scale().
-			WithAPIVersion("autoscaling/v1").
-			WithKind("Scale").
-			WithName(r.Name).
-			WithNamespace(r.Namespace).
-			WithSpec(applyconfigurationsautoscalingv1.ScaleSpec().
-				WithReplicas(1)),
-		v1.ApplyOptions{
-			FieldManager: r.FieldManager,
-			Force:        true,
-		}); err != nil {
+	if err := r.delete(ctx, selector); err != nil {
 		return err
 	}
-	return nil
+	lastPhase := capability.PhaseTerminating
+	progress(lastPhase)
+
+	timeout := r.Timeout
+	if timeout <= 0 {
+		timeout = defaultRestartTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(restartPollInterval)
+	defer ticker.Stop()
+
+	var stuck *corev1.Pod
+	for {
+		select {
+		case <-ctx.Done():
+			return r.failure(lastPhase, stuck, ctx.Err())
+		case <-ticker.C:
+		}
+
+		pods, err := r.ClientSet.CoreV1().Pods(r.Namespace).List(ctx, v1.ListOptions{
+			LabelSelector: selector.String(),
+		})
+		if err != nil {
+			continue
+		}
+
+		phase := capability.PhaseTerminating
+		var terminating, replacement *corev1.Pod
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			if replaced[pod.UID] {
+				terminating = pod
+			} else if replacement == nil || replacement.CreationTimestamp.Before(&pod.CreationTimestamp) {
+				replacement = pod
+			}
+		}
+		stuck = terminating
+		if terminating == nil {
+			phase = phaseOf(replacement)
+			stuck = replacement
+		}
+
+		if phase != lastPhase {
+			lastPhase = phase
+			progress(phase)
+		}
+		if phase == capability.PhaseReady {
+			return nil
+		}
+	}
 }
 
-// PodRestarter allows to restart by deleting pods matching a certain label
-type PodRestarter struct {
-	Namespace  string
-	LabelKey   string
-	LabelValue string
-	ClientSet  *kubernetes.Clientset
+// failure of a tracked restart in phase including the events of the pod it got stuck on
+func (r PodRestarter) failure(phase capability.Phase, stuck *corev1.Pod, err error) error {
+	failure := &capability.RestartFailure{Phase: phase, Err: err}
+	if stuck == nil {
+		return failure
+	}
+
+	// the tracking context is done already, so events are fetched with a fresh one
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	events, eventErr := podEvents(ctx, r.ClientSet, stuck, restartEventLimit, corev1.EventTypeWarning)
+	if eventErr != nil {
+		events = []string{fmt.Sprintf("failed to get events: %s", eventErr)}
+	}
+	failure.Events = events
+	return failure
 }
 
-// Restart the app by deleting its pod
-func (r PodRestarter) Restart(ctx context.Context) error {
+func (r PodRestarter) selector() (labels.Selector, error) {
 	req, err := labels.NewRequirement(r.LabelKey, selection.Equals, []string{r.LabelValue})
 	if err != nil {
-		return err
+		return nil, err
 	}
+	return labels.NewSelector().Add(*req), nil
+}
 
-	return r.ClientSet.CoreV1().Pods(r.Namespace).DeleteCollection(context.TODO(), v1.DeleteOptions{}, v1.ListOptions{
-		LabelSelector: labels.NewSelector().Add(*req).String(),
+func (r PodRestarter) delete(ctx context.Context, selector labels.Selector) error {
+	options := v1.DeleteOptions{}
+	if r.GracePeriod > 0 {
+		seconds := int64(r.GracePeriod.Seconds())
+		options.GracePeriodSeconds = &seconds
+	}
+	return r.ClientSet.CoreV1().Pods(r.Namespace).DeleteCollection(ctx, options, v1.ListOptions{
+		LabelSelector: selector.String(),
 	})
 }
@@ -0,0 +1,180 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/playnet-public/mc-bot/pkg/capability"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+const kubeconfig = `apiVersion: v1
+kind: Config
+current-context: home
+clusters:
+- name: home
+  cluster:
+    server: https://home.example:6443
+- name: lab
+  cluster:
+    server: https://lab.example:6443
+users:
+- name: bot
+  user:
+    token: secret
+contexts:
+- name: home
+  context:
+    cluster: home
+    user: bot
+- name: lab
+  context:
+    cluster: lab
+    user: bot
+`
+
+func TestConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte(kubeconfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("KUBECONFIG", path)
+
+	cases := []struct {
+		context string
+		host    string
+	}{
+		{context: "", host: "https://home.example:6443"},
+		{context: "lab", host: "https://lab.example:6443"},
+	}
+	for _, c := range cases {
+		config, err := Config(c.context)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if config.Host != c.host || config.BearerToken != "secret" {
+			t.Errorf("expected context %q to use %s, got %s", c.context, c.host, config.Host)
+		}
+	}
+
+	if _, err := Config("missing"); err == nil {
+		t.Error("expected an unknown context to fail")
+	}
+}
+
+// replacePods on deletion, creating replacement for every deleted pod
+func replacePods(clientset *fake.Clientset, replacement func(deleted corev1.Pod) *corev1.Pod) *[]k8stesting.DeleteCollectionAction {
+	deletions := &[]k8stesting.DeleteCollectionAction{}
+	clientset.PrependReactor("delete-collection", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		deletion := action.(k8stesting.DeleteCollectionAction)
+		*deletions = append(*deletions, deletion)
+
+		pods, err := clientset.Tracker().List(corev1.SchemeGroupVersion.WithResource("pods"), corev1.SchemeGroupVersion.WithKind("Pod"), testNamespace)
+		if err != nil {
+			return true, nil, err
+		}
+		for _, deleted := range pods.(*corev1.PodList).Items {
+			if !deletion.GetListRestrictions().Labels.Matches(labels.Set(deleted.Labels)) {
+				continue
+			}
+			if err := clientset.Tracker().Delete(corev1.SchemeGroupVersion.WithResource("pods"), testNamespace, deleted.Name); err != nil {
+				return true, nil, err
+			}
+			if err := clientset.Tracker().Add(replacement(deleted)); err != nil {
+				return true, nil, err
+			}
+		}
+		return true, nil, nil
+	})
+	return deletions
+}
+
+func TestPodRestarterRestart(t *testing.T) {
+	clientset := fake.NewSimpleClientset(pod("valheim-0", "valheim", "old", time.Now()), pod("other-0", "other", "other", time.Now()))
+	deletions := replacePods(clientset, func(deleted corev1.Pod) *corev1.Pod {
+		return pod(deleted.Name, deleted.Labels["app"], "new", time.Now())
+	})
+
+	err := PodRestarter{Namespace: testNamespace, LabelKey: "app", LabelValue: "valheim", ClientSet: clientset}.Restart(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(*deletions) != 1 {
+		t.Fatalf("expected one deletion, got %d", len(*deletions))
+	}
+	if selector := (*deletions)[0].GetListRestrictions().Labels.String(); selector != "app=valheim" {
+		t.Errorf("expected pods to be selected by app=valheim, got %s", selector)
+	}
+	other, err := clientset.CoreV1().Pods(testNamespace).Get(context.Background(), "other-0", v1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.UID != "other" {
+		t.Error("expected pods of other apps to be kept")
+	}
+}
+
+func TestPodRestarterRestartTracked(t *testing.T) {
+	clientset := fake.NewSimpleClientset(pod("valheim-0", "valheim", "old", time.Now()))
+	replacePods(clientset, func(deleted corev1.Pod) *corev1.Pod {
+		replacement := pod(deleted.Name, "valheim", "new", time.Now())
+		replacement.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+		return replacement
+	})
+
+	var phases []capability.Phase
+	err := PodRestarter{Namespace: testNamespace, LabelKey: "app", LabelValue: "valheim", ClientSet: clientset}.
+		RestartTracked(context.Background(), func(phase capability.Phase) {
+			phases = append(phases, phase)
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(phases) != 2 || phases[0] != capability.PhaseTerminating || phases[1] != capability.PhaseReady {
+		t.Errorf("expected phases Terminating and Ready, got %v", phases)
+	}
+}
+
+func TestPodRestarterRestartTrackedFailure(t *testing.T) {
+	clientset := fake.NewSimpleClientset(pod("valheim-0", "valheim", "old", time.Now()))
+	replacePods(clientset, func(deleted corev1.Pod) *corev1.Pod {
+		replacement := pod(deleted.Name, "valheim", "new", time.Now())
+		replacement.Status.ContainerStatuses = []corev1.ContainerStatus{{
+			State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+		}}
+		return replacement
+	})
+	if err := clientset.Tracker().Add(&corev1.Event{
+		ObjectMeta:     v1.ObjectMeta{Name: "valheim-0.backoff", Namespace: testNamespace},
+		InvolvedObject: corev1.ObjectReference{Name: "valheim-0", UID: "new"},
+		Type:           corev1.EventTypeWarning,
+		Reason:         "BackOff",
+		Message:        "Back-off restarting failed container",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	err := PodRestarter{Namespace: testNamespace, LabelKey: "app", LabelValue: "valheim", ClientSet: clientset, Timeout: 3 * time.Second}.
+		RestartTracked(context.Background(), func(capability.Phase) {})
+
+	var failure *capability.RestartFailure
+	if !errors.As(err, &failure) {
+		t.Fatalf("expected restart failure, got %v", err)
+	}
+	if failure.Phase != "Waiting: CrashLoopBackOff" {
+		t.Errorf("expected restart to be stuck in CrashLoopBackOff, got %q", failure.Phase)
+	}
+	if len(failure.Events) != 1 || failure.Events[0] != "Warning BackOff: Back-off restarting failed container" {
+		t.Errorf("expected warning events of the stuck pod, got %v", failure.Events)
+	}
+}
@@ -0,0 +1,29 @@
+package kubernetes
+
+import (
+	"errors"
+	"os"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// Config uses the in-cluster config when running in a cluster without KUBECONFIG or kubeContext
+// being set and falls back to the default kubeconfig otherwise, using kubeContext if set
+func Config(kubeContext string) (*rest.Config, error) {
+	if len(os.Getenv("KUBECONFIG")) < 1 && len(kubeContext) < 1 {
+		config, err := rest.InClusterConfig()
+		if err == nil {
+			return config, nil
+		}
+		if !errors.Is(err, rest.ErrNotInCluster) {
+			return nil, err
+		}
+	}
+
+	// the default loading rules respect the KUBECONFIG environment variable
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		clientcmd.NewDefaultClientConfigLoadingRules(),
+		&clientcmd.ConfigOverrides{CurrentContext: kubeContext},
+	).ClientConfig()
+}
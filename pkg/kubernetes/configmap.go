@@ -0,0 +1,52 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ConfigMapFile stores a file in the key of a ConfigMap
+type ConfigMapFile struct {
+	Namespace    string
+	Name         string
+	Key          string
+	ClientSet    kubernetes.Interface
+	FieldManager string
+	// Optional files load empty if the key is missing instead of failing
+	Optional bool
+}
+
+// Load the content of the file
+func (f ConfigMapFile) Load(ctx context.Context) (string, error) {
+	cm, err := f.ClientSet.CoreV1().ConfigMaps(f.Namespace).Get(ctx, f.Name, v1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	content, ok := cm.Data[f.Key]
+	if !ok && f.Optional {
+		return "", nil
+	}
+	if !ok {
+		return "", fmt.Errorf("configmap %s has no key %s", f.Name, f.Key)
+	}
+	return content, nil
+}
+
+// Save content into the key of the ConfigMap
+func (f ConfigMapFile) Save(ctx context.Context, content string) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"data": map[string]string{
+			f.Key: content,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = f.ClientSet.CoreV1().ConfigMaps(f.Namespace).Patch(ctx, f.Name, types.MergePatchType, patch, v1.PatchOptions{FieldManager: f.FieldManager})
+	return err
+}
@@ -0,0 +1,101 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/playnet-public/mc-bot/pkg/capability"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Health returns the replica counts and state of the newest pod of the workload
+func (w Workload) Health(ctx context.Context, warningLimit int) (capability.ServerHealth, error) {
+	health := capability.ServerHealth{}
+
+	desired, ready, err := w.replicaStatus(ctx)
+	if err != nil {
+		return health, err
+	}
+	health.DesiredReplicas = desired
+	health.ReadyReplicas = ready
+
+	pod, err := w.newestPod(ctx)
+	if err != nil {
+		return health, err
+	}
+	if pod == nil {
+		return health, nil
+	}
+
+	health.Pod = pod.Name
+	health.PodPhase = string(pod.Status.Phase)
+	health.Node = pod.Spec.NodeName
+	health.Created = pod.CreationTimestamp.Time
+	for _, status := range pod.Status.ContainerStatuses {
+		health.Restarts += status.RestartCount
+		if terminated := status.LastTerminationState.Terminated; terminated != nil {
+			health.LastTermination = fmt.Sprintf("%s (exit code %d) %s ago",
+				terminated.Reason,
+				terminated.ExitCode,
+				time.Since(terminated.FinishedAt.Time).Round(time.Second),
+			)
+		}
+	}
+
+	health.Warnings, err = podEvents(ctx, w.ClientSet, pod, warningLimit, corev1.EventTypeWarning)
+	if err != nil {
+		return health, err
+	}
+
+	return health, nil
+}
+
+// replicaStatus returns the desired and ready replicas of the workload
+func (w Workload) replicaStatus(ctx context.Context) (int32, int32, error) {
+	switch w.Resource {
+	case StatefulSets:
+		sts, err := w.ClientSet.AppsV1().StatefulSets(w.Namespace).Get(ctx, w.Name, v1.GetOptions{})
+		if err != nil {
+			return 0, 0, err
+		}
+		return replicasOrDefault(sts.Spec.Replicas), sts.Status.ReadyReplicas, nil
+	case Deployments:
+		deploy, err := w.ClientSet.AppsV1().Deployments(w.Namespace).Get(ctx, w.Name, v1.GetOptions{})
+		if err != nil {
+			return 0, 0, err
+		}
+		return replicasOrDefault(deploy.Spec.Replicas), deploy.Status.ReadyReplicas, nil
+	}
+
+	desired, err := w.Replicas(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	selector, err := w.selector(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	pods, err := w.ClientSet.CoreV1().Pods(w.Namespace).List(ctx, v1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	var ready int32
+	for i := range pods.Items {
+		if phaseOf(&pods.Items[i]) == capability.PhaseReady {
+			ready++
+		}
+	}
+	return desired, ready, nil
+}
+
+// replicasOrDefault returns the replicas of a spec, which default to 1 if unset
+func replicasOrDefault(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
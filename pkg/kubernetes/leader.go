@@ -0,0 +1,113 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/seibert-media/golibs/log"
+	"go.uber.org/zap"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+// Default timings of the leader election, allowing a standby to take over within seconds
+const (
+	DefaultLeaseDuration = 15 * time.Second
+	DefaultRenewDeadline = 10 * time.Second
+	DefaultRetryPeriod   = 2 * time.Second
+)
+
+// ErrLeadershipLost indicates the lease was lost while leading
+var ErrLeadershipLost = errors.New("leadership lost")
+
+// LeaderElector elects a single leader between replicas using a coordination.k8s.io Lease
+type LeaderElector struct {
+	Namespace string
+	// Name of the Lease
+	Name string
+	// Identity of this replica, usually the pod name
+	Identity  string
+	ClientSet kubernetes.Interface
+
+	// Timings are the defaults if unset
+	LeaseDuration time.Duration
+	RenewDeadline time.Duration
+	RetryPeriod   time.Duration
+}
+
+// Run waits for the leadership and calls lead with a context that's cancelled once it's lost.
+// The lease is released when lead returns, so a standby can take over right away.
+// It returns the error of lead or ErrLeadershipLost if the lease couldn't be renewed and nil
+// if ctx is done before leading
+func (e LeaderElector) Run(ctx context.Context, lead func(ctx context.Context) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	ctx = log.WithFields(ctx, zap.String("lease", e.Name), zap.String("identity", e.Identity))
+
+	started := make(chan struct{})
+	result := make(chan error, 1)
+
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock: &resourcelock.LeaseLock{
+			LeaseMeta: v1.ObjectMeta{
+				Namespace: e.Namespace,
+				Name:      e.Name,
+			},
+			Client: e.ClientSet.CoordinationV1(),
+			LockConfig: resourcelock.ResourceLockConfig{
+				Identity: e.Identity,
+			},
+		},
+		LeaseDuration:   durationOrDefault(e.LeaseDuration, DefaultLeaseDuration),
+		RenewDeadline:   durationOrDefault(e.RenewDeadline, DefaultRenewDeadline),
+		RetryPeriod:     durationOrDefault(e.RetryPeriod, DefaultRetryPeriod),
+		ReleaseOnCancel: true,
+		Name:            e.Name,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(leaderCtx context.Context) {
+				close(started)
+				log.From(ctx).Info("started leading")
+				err := lead(leaderCtx)
+				if err == nil && leaderCtx.Err() != nil && ctx.Err() == nil {
+					err = ErrLeadershipLost
+				}
+				result <- err
+				// stop renewing so the lease is released
+				cancel()
+			},
+			OnStoppedLeading: func() {
+				log.From(ctx).Info("stopped leading")
+			},
+			OnNewLeader: func(identity string) {
+				if identity != e.Identity {
+					log.From(ctx).Info("following leader", zap.String("leader", identity))
+				}
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	log.From(ctx).Info("waiting for leadership")
+	elector.Run(ctx)
+
+	select {
+	case <-started:
+		return <-result
+	default:
+		// the elector only returns without leading once the parent context is done,
+		// e.g. a standby shutting down, which isn't a failure
+		return nil
+	}
+}
+
+func durationOrDefault(d, fallback time.Duration) time.Duration {
+	if d <= 0 {
+		return fallback
+	}
+	return d
+}
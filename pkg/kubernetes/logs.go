@@ -0,0 +1,60 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/playnet-public/mc-bot/pkg/capability"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// maxLogBytes limits the logs fetched at once
+const maxLogBytes = 1 << 20
+
+// ErrNoPod indicates the workload has no pod right now
+var ErrNoPod = errors.New("no pod found")
+
+// Logs of the newest workload pod, returning the pod name and its logs
+func (w Workload) Logs(ctx context.Context, opts capability.LogOptions) (string, string, error) {
+	pod, err := w.newestPod(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	if pod == nil {
+		return "", "", ErrNoPod
+	}
+
+	podOpts := &corev1.PodLogOptions{
+		Container:  opts.Container,
+		Previous:   opts.Previous,
+		LimitBytes: int64Ptr(maxLogBytes),
+	}
+	// pods with multiple containers require one to be selected, the server is expected
+	// to run in the first one like for upgrades
+	if len(podOpts.Container) < 1 && len(pod.Spec.Containers) > 0 {
+		podOpts.Container = pod.Spec.Containers[0].Name
+	}
+	if opts.Lines > 0 {
+		podOpts.TailLines = int64Ptr(opts.Lines)
+	}
+	if opts.Since > 0 {
+		podOpts.SinceSeconds = int64Ptr(int64(opts.Since.Seconds()))
+	}
+
+	stream, err := w.ClientSet.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, podOpts).Stream(ctx)
+	if err != nil {
+		return pod.Name, "", err
+	}
+	defer stream.Close()
+
+	logs, err := io.ReadAll(io.LimitReader(stream, maxLogBytes))
+	if err != nil {
+		return pod.Name, "", err
+	}
+	return pod.Name, string(logs), nil
+}
+
+func int64Ptr(i int64) *int64 {
+	return &i
+}
@@ -0,0 +1,131 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/playnet-public/mc-bot/pkg/capability"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+// newestPod matching selector or nil if there is none
+func newestPod(ctx context.Context, clientset kubernetes.Interface, namespace string, selector labels.Selector) (*corev1.Pod, error) {
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, v1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var newest *corev1.Pod
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if newest == nil || newest.CreationTimestamp.Before(&pod.CreationTimestamp) {
+			newest = pod
+		}
+	}
+	return newest, nil
+}
+
+// phaseOf pod, which might be nil if it doesn't exist yet
+func phaseOf(pod *corev1.Pod) capability.Phase {
+	if pod == nil || pod.DeletionTimestamp != nil {
+		return capability.PhaseScaling
+	}
+	if len(pod.Spec.NodeName) < 1 {
+		return capability.PhaseScheduling
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady && condition.Status == corev1.ConditionTrue {
+			return capability.PhaseReady
+		}
+	}
+	for _, status := range pod.Status.InitContainerStatuses {
+		if status.State.Terminated != nil && status.State.Terminated.ExitCode == 0 {
+			continue
+		}
+		if phase, stuck := containerPhase(status); stuck {
+			return phase
+		}
+		return capability.PhaseInitializing
+	}
+	if len(pod.Status.ContainerStatuses) < 1 {
+		return capability.PhaseCreating
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if phase, stuck := containerPhase(status); stuck || status.State.Running == nil {
+			return phase
+		}
+	}
+	return capability.PhaseStarting
+}
+
+// containerPhase of a container which is not running yet. It's stuck if it's waiting for any
+// reason besides being created, e.g. CrashLoopBackOff or ImagePullBackOff, which is reported as is
+func containerPhase(status corev1.ContainerStatus) (capability.Phase, bool) {
+	switch {
+	case status.State.Waiting != nil:
+		switch reason := status.State.Waiting.Reason; reason {
+		case "", "ContainerCreating", "PodInitializing":
+			return capability.PhaseCreating, false
+		default:
+			return capability.Phase("Waiting: " + reason), true
+		}
+	case status.State.Terminated != nil:
+		reason := status.State.Terminated.Reason
+		if len(reason) < 1 {
+			reason = fmt.Sprintf("exit code %d", status.State.Terminated.ExitCode)
+		}
+		return capability.Phase("Terminated: " + reason), true
+	default:
+		return capability.PhaseCreating, false
+	}
+}
+
+// podEvents returns the last limit events involving pod formatted for humans.
+// Events are filtered by eventType if set, e.g. corev1.EventTypeWarning
+func podEvents(ctx context.Context, clientset kubernetes.Interface, pod *corev1.Pod, limit int, eventType string) ([]string, error) {
+	selectors := []fields.Selector{
+		fields.OneTermEqualSelector("involvedObject.name", pod.Name),
+		fields.OneTermEqualSelector("involvedObject.uid", string(pod.UID)),
+	}
+	if len(eventType) > 0 {
+		selectors = append(selectors, fields.OneTermEqualSelector("type", eventType))
+	}
+	events, err := clientset.CoreV1().Events(pod.Namespace).List(ctx, v1.ListOptions{
+		FieldSelector: fields.AndSelectors(selectors...).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items := events.Items
+	sort.Slice(items, func(i, j int) bool {
+		return eventTime(items[i]).Before(eventTime(items[j]))
+	})
+	if len(items) > limit {
+		items = items[len(items)-limit:]
+	}
+
+	formatted := make([]string, 0, len(items))
+	for _, event := range items {
+		formatted = append(formatted, fmt.Sprintf("%s %s: %s", event.Type, event.Reason, event.Message))
+	}
+	return formatted, nil
+}
+
+func eventTime(event corev1.Event) time.Time {
+	if !event.LastTimestamp.IsZero() {
+		return event.LastTimestamp.Time
+	}
+	if !event.EventTime.IsZero() {
+		return event.EventTime.Time
+	}
+	return event.CreationTimestamp.Time
+}
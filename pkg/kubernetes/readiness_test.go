@@ -0,0 +1,138 @@
+package kubernetes
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/playnet-public/mc-bot/pkg/capability"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const testNamespace = "games"
+
+func statefulSet(name string) *appsv1.StatefulSet {
+	return &appsv1.StatefulSet{
+		ObjectMeta: v1.ObjectMeta{Name: name, Namespace: testNamespace},
+		Spec: appsv1.StatefulSetSpec{
+			Selector: &v1.LabelSelector{MatchLabels: map[string]string{"app": name}},
+		},
+	}
+}
+
+func pod(name, app string, uid types.UID, created time.Time) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: v1.ObjectMeta{
+			Name:              name,
+			Namespace:         testNamespace,
+			UID:               uid,
+			Labels:            map[string]string{"app": app},
+			CreationTimestamp: v1.NewTime(created),
+		},
+		Spec: corev1.PodSpec{NodeName: "node"},
+	}
+}
+
+func TestWorkloadPhase(t *testing.T) {
+	now := time.Now()
+	older := pod("server-old", "server", "old", now.Add(-time.Hour))
+	older.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+	newer := pod("server-new", "server", "new", now)
+	newer.Status.ContainerStatuses = []corev1.ContainerStatus{{
+		State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+	}}
+	clientset := fake.NewSimpleClientset(statefulSet("server"), older, newer)
+
+	phase, err := Workload{Namespace: testNamespace, Name: "server", Resource: StatefulSets, ClientSet: clientset}.Phase(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if phase != "Waiting: CrashLoopBackOff" {
+		t.Errorf("expected phase of the newest pod, got %q", phase)
+	}
+}
+
+func TestPhaseOf(t *testing.T) {
+	running := corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}
+	cases := []struct {
+		name     string
+		pod      *corev1.Pod
+		expected capability.Phase
+	}{
+		{name: "missing", pod: nil, expected: capability.PhaseScaling},
+		{name: "unscheduled", pod: &corev1.Pod{}, expected: capability.PhaseScheduling},
+		{
+			name:     "no statuses",
+			pod:      &corev1.Pod{Spec: corev1.PodSpec{NodeName: "node"}},
+			expected: capability.PhaseCreating,
+		},
+		{
+			name: "pulling",
+			pod: &corev1.Pod{
+				Spec: corev1.PodSpec{NodeName: "node"},
+				Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+					State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ContainerCreating"}},
+				}}},
+			},
+			expected: capability.PhaseCreating,
+		},
+		{
+			name: "image pull failing",
+			pod: &corev1.Pod{
+				Spec: corev1.PodSpec{NodeName: "node"},
+				Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+					State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff"}},
+				}}},
+			},
+			expected: "Waiting: ImagePullBackOff",
+		},
+		{
+			name: "initializing",
+			pod: &corev1.Pod{
+				Spec:   corev1.PodSpec{NodeName: "node"},
+				Status: corev1.PodStatus{InitContainerStatuses: []corev1.ContainerStatus{{State: running}}},
+			},
+			expected: capability.PhaseInitializing,
+		},
+		{
+			name: "init failed",
+			pod: &corev1.Pod{
+				Spec: corev1.PodSpec{NodeName: "node"},
+				Status: corev1.PodStatus{InitContainerStatuses: []corev1.ContainerStatus{{
+					State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Reason: "Error"}},
+				}}},
+			},
+			expected: "Terminated: Error",
+		},
+		{
+			name: "starting",
+			pod: &corev1.Pod{
+				Spec:   corev1.PodSpec{NodeName: "node"},
+				Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{State: running}}},
+			},
+			expected: capability.PhaseStarting,
+		},
+		{
+			name: "ready",
+			pod: &corev1.Pod{
+				Spec: corev1.PodSpec{NodeName: "node"},
+				Status: corev1.PodStatus{
+					Conditions:        []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+					ContainerStatuses: []corev1.ContainerStatus{{State: running}},
+				},
+			},
+			expected: capability.PhaseReady,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if phase := phaseOf(c.pod); phase != c.expected {
+				t.Errorf("expected %q, got %q", c.expected, phase)
+			}
+		})
+	}
+}
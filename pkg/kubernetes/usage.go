@@ -0,0 +1,67 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/playnet-public/mc-bot/pkg/capability"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// podMetrics of the metrics.k8s.io API, decoded manually to avoid depending on its clientset
+type podMetrics struct {
+	Containers []struct {
+		Name  string              `json:"name"`
+		Usage corev1.ResourceList `json:"usage"`
+	} `json:"containers"`
+}
+
+// Usage reads the resource usage of the newest workload pod from the metrics API
+func (w Workload) Usage(ctx context.Context) (capability.ResourceUsage, error) {
+	pod, err := w.newestPod(ctx)
+	if err != nil {
+		return capability.ResourceUsage{}, err
+	}
+	if pod == nil {
+		return capability.ResourceUsage{}, ErrNoPod
+	}
+
+	client, err := w.restClient()
+	if err != nil {
+		return capability.ResourceUsage{}, err
+	}
+	raw, err := client.Get().
+		AbsPath("/apis/metrics.k8s.io/v1beta1", "namespaces", pod.Namespace, "pods", pod.Name).
+		DoRaw(ctx)
+	if err != nil {
+		return capability.ResourceUsage{}, fmt.Errorf("reading metrics of %s, is the metrics-server installed? %w", pod.Name, err)
+	}
+	metrics := podMetrics{}
+	if err := json.Unmarshal(raw, &metrics); err != nil {
+		return capability.ResourceUsage{}, fmt.Errorf("decoding metrics of %s: %w", pod.Name, err)
+	}
+
+	usage := capability.ResourceUsage{
+		Pod:  pod.Name,
+		Node: pod.Spec.NodeName,
+	}
+	for _, metric := range metrics.Containers {
+		container := capability.ContainerUsage{
+			Name:   metric.Name,
+			CPU:    metric.Usage.Cpu().MilliValue(),
+			Memory: metric.Usage.Memory().Value(),
+		}
+		for _, spec := range pod.Spec.Containers {
+			if spec.Name != metric.Name {
+				continue
+			}
+			container.CPURequest = spec.Resources.Requests.Cpu().MilliValue()
+			container.CPULimit = spec.Resources.Limits.Cpu().MilliValue()
+			container.MemoryRequest = spec.Resources.Requests.Memory().Value()
+			container.MemoryLimit = spec.Resources.Limits.Memory().Value()
+		}
+		usage.Containers = append(usage.Containers, container)
+	}
+	return usage, nil
+}
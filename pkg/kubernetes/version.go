@@ -0,0 +1,233 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/playnet-public/mc-bot/pkg/capability"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// DefaultVersionEnv selects the server version in the itzg/minecraft-server image
+const DefaultVersionEnv = "VERSION"
+
+// ErrSameVersion indicates the server already runs the requested version
+var ErrSameVersion = errors.New("server already runs this version")
+
+// ErrInvalidVersion indicates a version which can't be used as image tag
+var ErrInvalidVersion = errors.New("versions may only contain up to 128 letters, digits, underscores, periods and dashes")
+
+// versionRegex matches valid image tags
+var versionRegex = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]{0,127}$`)
+
+// ValidateVersion returns ErrInvalidVersion if version can't be used as image tag
+func ValidateVersion(version string) error {
+	if !versionRegex.MatchString(version) {
+		return ErrInvalidVersion
+	}
+	return nil
+}
+
+// Spec returns the version of the server container, which is the first one in the pod template
+func (w Workload) Spec(ctx context.Context) (capability.ServerSpec, error) {
+	template, err := w.podTemplate(ctx)
+	if err != nil {
+		return capability.ServerSpec{}, err
+	}
+	if len(template.Spec.Containers) < 1 {
+		return capability.ServerSpec{}, fmt.Errorf("%s has no containers", w.Name)
+	}
+	container := template.Spec.Containers[0]
+
+	spec := capability.ServerSpec{
+		Container: container.Name,
+		Image:     container.Image,
+		Version:   imageTag(container.Image),
+	}
+	if index := envIndex(container, w.versionEnv()); index >= 0 {
+		spec.Version = container.Env[index].Value
+		spec.VersionEnv = w.versionEnv()
+	}
+	return spec, nil
+}
+
+// Upgrade the server to version by setting the version env if the container uses it
+// or the image tag otherwise. It returns the previous spec for rolling back
+func (w Workload) Upgrade(ctx context.Context, version string) (capability.ServerSpec, error) {
+	if err := ValidateVersion(version); err != nil {
+		return capability.ServerSpec{}, err
+	}
+	previous, err := w.Spec(ctx)
+	if err != nil {
+		return previous, err
+	}
+	if previous.Version == version {
+		return previous, ErrSameVersion
+	}
+
+	next := previous
+	next.Version = version
+	if len(next.VersionEnv) < 1 {
+		next.Image = withTag(previous.Image, version)
+	}
+	return previous, w.apply(ctx, next)
+}
+
+// Rollback the server to the previous spec returned by Upgrade
+func (w Workload) Rollback(ctx context.Context, previous capability.ServerSpec) error {
+	if err := w.apply(ctx, previous); err != nil {
+		return err
+	}
+	if w.Resource != StatefulSets {
+		return nil
+	}
+
+	// StatefulSets don't replace a broken pod by themselves, so it's deleted
+	// to force the rollback, see https://kubernetes.io/docs/concepts/workloads/controllers/statefulset/#forced-rollback
+	pod, err := w.newestPod(ctx)
+	if err != nil || pod == nil || phaseOf(pod) == capability.PhaseReady {
+		return err
+	}
+	return w.ClientSet.CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, v1.DeleteOptions{})
+}
+
+// RolledOut returns if the newest workload pod was created after since and is ready
+func (w Workload) RolledOut(ctx context.Context, since time.Time) (bool, error) {
+	pod, err := w.newestPod(ctx)
+	if err != nil || pod == nil {
+		return false, err
+	}
+	// creation timestamps are truncated to seconds
+	if pod.CreationTimestamp.Time.Before(since.Truncate(time.Second)) {
+		return false, nil
+	}
+	return phaseOf(pod) == capability.PhaseReady, nil
+}
+
+// apply spec to the server container using a JSON patch, which fails if the containers changed
+func (w Workload) apply(ctx context.Context, spec capability.ServerSpec) error {
+	template, err := w.podTemplate(ctx)
+	if err != nil {
+		return err
+	}
+	if len(template.Spec.Containers) < 1 || template.Spec.Containers[0].Name != spec.Container {
+		return fmt.Errorf("container %s of %s has changed", spec.Container, w.Name)
+	}
+	container := template.Spec.Containers[0]
+
+	containerPath := "/spec/template/spec/containers/0"
+	ops := []map[string]interface{}{
+		{"op": "test", "path": containerPath + "/name", "value": spec.Container},
+		{"op": "replace", "path": containerPath + "/image", "value": spec.Image},
+	}
+	if len(spec.VersionEnv) > 0 {
+		index := envIndex(container, spec.VersionEnv)
+		if index < 0 {
+			return fmt.Errorf("container %s has no %s env", spec.Container, spec.VersionEnv)
+		}
+		envPath := fmt.Sprintf("%s/env/%d", containerPath, index)
+		ops = append(ops,
+			map[string]interface{}{"op": "test", "path": envPath + "/name", "value": spec.VersionEnv},
+			map[string]interface{}{"op": "replace", "path": envPath + "/value", "value": spec.Version},
+		)
+	}
+
+	patch, err := json.Marshal(ops)
+	if err != nil {
+		return err
+	}
+	return w.patch(ctx, types.JSONPatchType, patch)
+}
+
+// podTemplate of the workload
+func (w Workload) podTemplate(ctx context.Context) (corev1.PodTemplateSpec, error) {
+	switch w.Resource {
+	case StatefulSets:
+		sts, err := w.ClientSet.AppsV1().StatefulSets(w.Namespace).Get(ctx, w.Name, v1.GetOptions{})
+		if err != nil {
+			return corev1.PodTemplateSpec{}, err
+		}
+		return sts.Spec.Template, nil
+	case Deployments:
+		deploy, err := w.ClientSet.AppsV1().Deployments(w.Namespace).Get(ctx, w.Name, v1.GetOptions{})
+		if err != nil {
+			return corev1.PodTemplateSpec{}, err
+		}
+		return deploy.Spec.Template, nil
+	}
+
+	client, err := w.restClient()
+	if err != nil {
+		return corev1.PodTemplateSpec{}, err
+	}
+	raw, err := client.Get().
+		AbsPath(w.path()...).
+		Do(ctx).
+		Raw()
+	if err != nil {
+		return corev1.PodTemplateSpec{}, err
+	}
+	workload := struct {
+		Spec struct {
+			Template corev1.PodTemplateSpec `json:"template"`
+		} `json:"spec"`
+	}{}
+	if err := json.Unmarshal(raw, &workload); err != nil {
+		return corev1.PodTemplateSpec{}, err
+	}
+	return workload.Spec.Template, nil
+}
+
+func (w Workload) versionEnv() string {
+	if len(w.VersionEnv) < 1 {
+		return DefaultVersionEnv
+	}
+	return w.VersionEnv
+}
+
+// envIndex returns the index of the plain env called name or -1 if there is none
+func envIndex(container corev1.Container, name string) int {
+	for i, env := range container.Env {
+		if env.Name == name && env.ValueFrom == nil {
+			return i
+		}
+	}
+	return -1
+}
+
+// imageTag returns the tag of image, which is "latest" if unset
+func imageTag(image string) string {
+	if index := strings.Index(image, "@"); index >= 0 {
+		return image[index+1:]
+	}
+	_, tag := splitTag(image)
+	if len(tag) < 1 {
+		return "latest"
+	}
+	return tag
+}
+
+// withTag returns image with its tag or digest replaced by tag
+func withTag(image, tag string) string {
+	if index := strings.Index(image, "@"); index >= 0 {
+		image = image[:index]
+	}
+	repository, _ := splitTag(image)
+	return repository + ":" + tag
+}
+
+// splitTag splits image into repository and tag, ignoring registry ports
+func splitTag(image string) (string, string) {
+	index := strings.LastIndex(image, ":")
+	if index < 0 || index < strings.LastIndex(image, "/") {
+		return image, ""
+	}
+	return image[:index], image[index+1:]
+}
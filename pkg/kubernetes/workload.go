@@ -0,0 +1,261 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/playnet-public/mc-bot/pkg/capability"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	applyconfigurationsautoscalingv1 "k8s.io/client-go/applyconfigurations/autoscaling/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+var (
+	// StatefulSets resource
+	StatefulSets = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "statefulsets"}
+	// Deployments resource
+	Deployments = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+)
+
+// restartedAtAnnotation is set on the pod template to cause a rollout restart
+const restartedAtAnnotation = "restarter.play-net.org/restartedAt"
+
+// ParseResource parses "statefulset", "deployment" or any other resource in the
+// form "resource.version.group", e.g. "servers.v1alpha1.example.com"
+func ParseResource(s string) (schema.GroupVersionResource, error) {
+	switch strings.ToLower(s) {
+	case "", "sts", "statefulset", "statefulsets":
+		return StatefulSets, nil
+	case "deploy", "deployment", "deployments":
+		return Deployments, nil
+	}
+	gvr, _ := schema.ParseResourceArg(s)
+	if gvr == nil {
+		return schema.GroupVersionResource{}, fmt.Errorf("invalid resource %q, expected resource.version.group", s)
+	}
+	return *gvr, nil
+}
+
+// Workload allows to restart and scale StatefulSets, Deployments and any other
+// resource with a scale subresource and a pod template
+type Workload struct {
+	Namespace    string
+	Name         string
+	Resource     schema.GroupVersionResource
+	ClientSet    kubernetes.Interface
+	FieldManager string
+	// VersionEnv selecting the server version in the container, DefaultVersionEnv if unset
+	VersionEnv string
+}
+
+// Restart the workload by patching its pod template, causing a rollout restart
+func (w Workload) Restart(ctx context.Context) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"annotations": map[string]string{
+						restartedAtAnnotation: time.Now().UTC().Format(time.RFC3339),
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return w.patch(ctx, types.MergePatchType, patch)
+}
+
+// patch the workload itself
+func (w Workload) patch(ctx context.Context, patchType types.PatchType, patch []byte) error {
+	var err error
+	opts := v1.PatchOptions{FieldManager: w.FieldManager}
+	switch w.Resource {
+	case StatefulSets:
+		_, err = w.ClientSet.AppsV1().StatefulSets(w.Namespace).Patch(ctx, w.Name, patchType, patch, opts)
+	case Deployments:
+		_, err = w.ClientSet.AppsV1().Deployments(w.Namespace).Patch(ctx, w.Name, patchType, patch, opts)
+	default:
+		client, clientErr := w.restClient()
+		if clientErr != nil {
+			return clientErr
+		}
+		err = client.Patch(patchType).
+			AbsPath(w.path()...).
+			Param("fieldManager", w.FieldManager).
+			Body(patch).
+			Do(ctx).
+			Error()
+	}
+	return err
+}
+
+// ScaleDown the workload to zero replicas
+func (w Workload) ScaleDown(ctx context.Context) error {
+	return w.scale(ctx, 0)
+}
+
+// ScaleUp the workload to one replica
+func (w Workload) ScaleUp(ctx context.Context) error {
+	return w.scale(ctx, 1)
+}
+
+func (w Workload) scale(ctx context.Context, replicas int32) error {
+	apply := applyconfigurationsautoscalingv1.Scale().
+		WithAPIVersion("autoscaling/v1").
+		WithKind("Scale").
+		WithName(w.Name).
+		WithNamespace(w.Namespace).
+		WithSpec(applyconfigurationsautoscalingv1.ScaleSpec().
+			WithReplicas(replicas))
+	opts := v1.ApplyOptions{
+		FieldManager: w.FieldManager,
+		Force:        true,
+	}
+
+	var err error
+	switch w.Resource {
+	case StatefulSets:
+		_, err = w.ClientSet.AppsV1().StatefulSets(w.Namespace).ApplyScale(ctx, w.Name, apply, opts)
+	case Deployments:
+		_, err = w.ClientSet.AppsV1().Deployments(w.Namespace).ApplyScale(ctx, w.Name, apply, opts)
+	default:
+		patch, marshalErr := json.Marshal(map[string]interface{}{
+			"spec": map[string]int32{"replicas": replicas},
+		})
+		if marshalErr != nil {
+			return marshalErr
+		}
+		client, clientErr := w.restClient()
+		if clientErr != nil {
+			return clientErr
+		}
+		err = client.Patch(types.MergePatchType).
+			AbsPath(append(w.path(), "scale")...).
+			Param("fieldManager", w.FieldManager).
+			Body(patch).
+			Do(ctx).
+			Error()
+	}
+	return err
+}
+
+// Replicas returns the number of desired replicas of the workload
+func (w Workload) Replicas(ctx context.Context) (int32, error) {
+	scale, err := w.getScale(ctx)
+	if err != nil {
+		return -1, err
+	}
+	return scale.Spec.Replicas, nil
+}
+
+// Phase returns the current phase of the workload coming up
+func (w Workload) Phase(ctx context.Context) (capability.Phase, error) {
+	pod, err := w.newestPod(ctx)
+	if err != nil {
+		return "", err
+	}
+	return phaseOf(pod), nil
+}
+
+// Events returns the last limit events of the newest workload pod
+func (w Workload) Events(ctx context.Context, limit int) ([]string, error) {
+	pod, err := w.newestPod(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if pod == nil {
+		return nil, nil
+	}
+	return podEvents(ctx, w.ClientSet, pod, limit, "")
+}
+
+func (w Workload) getScale(ctx context.Context) (*autoscalingv1.Scale, error) {
+	switch w.Resource {
+	case StatefulSets:
+		return w.ClientSet.AppsV1().StatefulSets(w.Namespace).GetScale(ctx, w.Name, v1.GetOptions{})
+	case Deployments:
+		return w.ClientSet.AppsV1().Deployments(w.Namespace).GetScale(ctx, w.Name, v1.GetOptions{})
+	}
+
+	client, err := w.restClient()
+	if err != nil {
+		return nil, err
+	}
+	raw, err := client.Get().
+		AbsPath(append(w.path(), "scale")...).
+		Do(ctx).
+		Raw()
+	if err != nil {
+		return nil, err
+	}
+	scale := &autoscalingv1.Scale{}
+	if err := json.Unmarshal(raw, scale); err != nil {
+		return nil, err
+	}
+	return scale, nil
+}
+
+// selector for the pods of the workload
+func (w Workload) selector(ctx context.Context) (labels.Selector, error) {
+	switch w.Resource {
+	case StatefulSets:
+		sts, err := w.ClientSet.AppsV1().StatefulSets(w.Namespace).Get(ctx, w.Name, v1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return v1.LabelSelectorAsSelector(sts.Spec.Selector)
+	case Deployments:
+		deploy, err := w.ClientSet.AppsV1().Deployments(w.Namespace).Get(ctx, w.Name, v1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return v1.LabelSelectorAsSelector(deploy.Spec.Selector)
+	}
+
+	scale, err := w.getScale(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(scale.Status.Selector) < 1 {
+		return nil, fmt.Errorf("%s %s does not expose a pod selector", w.Resource.Resource, w.Name)
+	}
+	return labels.Parse(scale.Status.Selector)
+}
+
+func (w Workload) newestPod(ctx context.Context) (*corev1.Pod, error) {
+	selector, err := w.selector(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newestPod(ctx, w.ClientSet, w.Namespace, selector)
+}
+
+// restClient for generic requests, which is not available with fake clientsets
+func (w Workload) restClient() (rest.Interface, error) {
+	client := w.ClientSet.Discovery().RESTClient()
+	if client == nil {
+		return nil, fmt.Errorf("generic requests for %s are not supported by this client", w.Resource.Resource)
+	}
+	return client, nil
+}
+
+// path of the workload for generic requests
+func (w Workload) path() []string {
+	prefix := []string{"/apis", w.Resource.Group, w.Resource.Version}
+	if len(w.Resource.Group) < 1 {
+		prefix = []string{"/api", w.Resource.Version}
+	}
+	return append(prefix, "namespaces", w.Namespace, w.Resource.Resource, w.Name)
+}
@@ -0,0 +1,73 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// recordScale records the replicas of scale patches to resource
+func recordScale(clientset *fake.Clientset, resource string) *[]int32 {
+	replicas := &[]int32{}
+	clientset.PrependReactor("patch", resource, func(action k8stesting.Action) (bool, runtime.Object, error) {
+		patch := action.(k8stesting.PatchAction)
+		if patch.GetPatchType() != types.ApplyPatchType {
+			return false, nil, nil
+		}
+		scale := &autoscalingv1.Scale{}
+		if err := json.Unmarshal(patch.GetPatch(), scale); err != nil {
+			return true, nil, err
+		}
+		*replicas = append(*replicas, scale.Spec.Replicas)
+		return true, scale, nil
+	})
+	return replicas
+}
+
+func TestWorkloadScale(t *testing.T) {
+	for _, resource := range []string{"statefulsets", "deployments"} {
+		t.Run(resource, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset()
+			replicas := recordScale(clientset, resource)
+			gvr, err := ParseResource(resource)
+			if err != nil {
+				t.Fatal(err)
+			}
+			w := Workload{Namespace: testNamespace, Name: "server", Resource: gvr, ClientSet: clientset}
+
+			if err := w.ScaleUp(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if err := w.ScaleDown(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if len(*replicas) != 2 || (*replicas)[0] != 1 || (*replicas)[1] != 0 {
+				t.Errorf("expected scaling to 1 and 0 replicas, got %v", *replicas)
+			}
+		})
+	}
+}
+
+func TestWorkloadReplicas(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependReactor("get", "statefulsets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "scale" {
+			return false, nil, nil
+		}
+		return true, &autoscalingv1.Scale{Spec: autoscalingv1.ScaleSpec{Replicas: 1}}, nil
+	})
+
+	replicas, err := Workload{Namespace: testNamespace, Name: "server", Resource: StatefulSets, ClientSet: clientset}.Replicas(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replicas != 1 {
+		t.Errorf("expected 1 replica, got %d", replicas)
+	}
+}
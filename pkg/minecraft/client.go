@@ -7,19 +7,30 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/playnet-public/mc-bot/pkg/capability"
 	"github.com/seibert-media/golibs/log"
-	rcon "github.com/willroberts/minecraft-client"
 	"go.uber.org/zap"
 )
 
 // CommandSender defines the minimal interface for sending RCON Commands
 type CommandSender interface {
-	SendCommand(ctx context.Context, command string) (rcon.Message, error)
+	SendCommand(ctx context.Context, command string) (capability.Response, error)
+}
+
+// ConsolePattern matches the responses to commands in the console output of vanilla and Spigot
+// based servers, capturing them without their log prefix
+var ConsolePattern = regexp.MustCompile(`^\[[^\]]+\] \[Server thread/INFO\]: (.*)$|^\[[0-9:]+ INFO\]: (.*)$`)
+
+// PlayerLister lists the online players
+type PlayerLister interface {
+	CountPlayers(ctx context.Context) (int, error)
+	Players(ctx context.Context) (int, []string, error)
 }
 
 // Client wraps a RCON connection exposing required features
 type Client struct {
-	rcon CommandSender
+	rcon    CommandSender
+	players PlayerLister
 }
 
 // NewClient with default settings
@@ -40,6 +51,20 @@ func (c Client) Setup(address string, password string) (Client, error) {
 	return c, nil
 }
 
+// WithCommandSender returns a Client sending its commands through sender
+// instead of a RCON session, e.g. the console of a supervised server process
+func (c Client) WithCommandSender(sender CommandSender) Client {
+	c.rcon = sender
+	return c
+}
+
+// WithPlayerLister returns a Client listing players through lister instead of the list command,
+// e.g. the server list ping if the command sender doesn't return any output
+func (c Client) WithPlayerLister(lister PlayerLister) Client {
+	c.players = lister
+	return c
+}
+
 // Whitelist the provided username
 func (c Client) Whitelist(ctx context.Context, username string) error {
 	msg, err := c.rcon.SendCommand(ctx, "whitelist add "+username)
@@ -63,10 +88,10 @@ func (c Client) Restart(ctx context.Context) error {
 }
 
 // SendCommand to the server via RCON
-func (c Client) SendCommand(ctx context.Context, command string) (rcon.Message, error) {
+func (c Client) SendCommand(ctx context.Context, command string) (capability.Response, error) {
 	msg, err := c.rcon.SendCommand(ctx, command)
 	if err != nil {
-		return rcon.Message{}, err
+		return capability.Response{}, err
 	}
 
 	log.From(ctx).Info("receiving sendCommand response", zap.String("payload", msg.Body))
@@ -91,8 +116,11 @@ var (
 	playersRegex     = regexp.MustCompile(`[A-Za-z\s]+([0-9]+)[A-Za-z\s]+([0-9]+)[A-Za-z\s]+:\s?([A-Za-z_,\s]+)*`)
 )
 
-// CountPlayers returns the number of players returned by the RCON list command
+// CountPlayers returns the number of players returned by the RCON list command or the player lister if set
 func (c Client) CountPlayers(ctx context.Context) (int, error) {
+	if c.players != nil {
+		return c.players.CountPlayers(ctx)
+	}
 	playerCount, _, err := c.listAndCountPlayers(ctx, playerCountRegex)
 	if err != nil {
 		return -1, err
@@ -101,8 +129,11 @@ func (c Client) CountPlayers(ctx context.Context) (int, error) {
 	return playerCount, nil
 }
 
-// Players returns the number of players and their names as returned by the RCON list command
+// Players returns the number of players and their names as returned by the RCON list command or the player lister if set
 func (c Client) Players(ctx context.Context) (int, []string, error) {
+	if c.players != nil {
+		return c.players.Players(ctx)
+	}
 	playerCount, res, err := c.listAndCountPlayers(ctx, playersRegex)
 	if err != nil {
 		return -1, nil, err
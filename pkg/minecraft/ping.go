@@ -0,0 +1,119 @@
+package minecraft
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+)
+
+// statusProtocolVersion sent in status pings, servers answer with their own version
+const statusProtocolVersion = -1
+
+// Pinger queries the status of a server using the server list ping
+type Pinger struct {
+	// Address of the game port, e.g. "minecraft:25565"
+	Address string
+	Timeout time.Duration
+}
+
+// Version of the server as shown in the server list
+func (p Pinger) Version(ctx context.Context) (string, error) {
+	status, err := p.status(ctx)
+	if err != nil {
+		return "", err
+	}
+	return status.Version.Name, nil
+}
+
+// anonymousPlayerID is sent in the sample for players hiding from the server list
+const anonymousPlayerID = "00000000-0000-0000-0000-000000000000"
+
+// CountPlayers returns the number of online players shown in the server list
+func (p Pinger) CountPlayers(ctx context.Context) (int, error) {
+	status, err := p.status(ctx)
+	if err != nil {
+		return -1, err
+	}
+	return status.Players.Online, nil
+}
+
+// Players returns the number of online players and the names in the sample of the server list.
+// The sample might not include every player, e.g. if they are hiding from the server list
+func (p Pinger) Players(ctx context.Context) (int, []string, error) {
+	status, err := p.status(ctx)
+	if err != nil {
+		return -1, nil, err
+	}
+	players := make([]string, 0, len(status.Players.Sample))
+	for _, player := range status.Players.Sample {
+		if player.ID == anonymousPlayerID {
+			continue
+		}
+		players = append(players, player.Name)
+	}
+	return status.Players.Online, players, nil
+}
+
+func (p Pinger) status(ctx context.Context) (statusResponse, error) {
+	timeout := p.Timeout
+	if timeout <= 0 {
+		timeout = 1 * time.Second
+	}
+	dialer := net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", p.Address)
+	if err != nil {
+		return statusResponse{}, err
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return statusResponse{}, err
+	}
+
+	host, portValue, err := net.SplitHostPort(p.Address)
+	if err != nil {
+		return statusResponse{}, err
+	}
+	port, err := strconv.ParseUint(portValue, 10, 16)
+	if err != nil {
+		return statusResponse{}, fmt.Errorf("invalid port %s: %w", portValue, err)
+	}
+
+	hs := &bytes.Buffer{}
+	writeVarInt(hs, statusProtocolVersion)
+	writeString(hs, host)
+	if err := binary.Write(hs, binary.BigEndian, uint16(port)); err != nil {
+		return statusResponse{}, err
+	}
+	writeVarInt(hs, stateStatus)
+	if err := writePacket(conn, 0x00, hs.Bytes()); err != nil {
+		return statusResponse{}, err
+	}
+	if err := writePacket(conn, 0x00, nil); err != nil {
+		return statusResponse{}, err
+	}
+
+	// status responses including favicons exceed the limit for client packets
+	resp, err := readPacketLimit(bufio.NewReader(conn), maxStatusLength)
+	if err != nil {
+		return statusResponse{}, err
+	}
+	if resp.id != 0x00 {
+		return statusResponse{}, fmt.Errorf("unexpected status packet id %d", resp.id)
+	}
+	payload, err := readString(resp.data)
+	if err != nil {
+		return statusResponse{}, err
+	}
+
+	status := statusResponse{}
+	if err := json.Unmarshal([]byte(payload), &status); err != nil {
+		return statusResponse{}, fmt.Errorf("decoding status: %w", err)
+	}
+	return status, nil
+}
@@ -0,0 +1,85 @@
+package minecraft
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"net"
+	"testing"
+
+	"github.com/playnet-public/mc-bot/pkg/capability"
+)
+
+// serveStatus answers a single server list ping with status
+func serveStatus(t *testing.T, status string) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		// handshake and status request
+		for i := 0; i < 2; i++ {
+			if _, err := readPacket(r); err != nil {
+				t.Errorf("reading ping: %v", err)
+				return
+			}
+		}
+		buf := &bytes.Buffer{}
+		writeString(buf, status)
+		if err := writePacket(conn, 0x00, buf.Bytes()); err != nil {
+			t.Errorf("writing status: %v", err)
+		}
+	}()
+	return listener.Addr().String()
+}
+
+// silentSender sends commands without any output, like the Pterodactyl panel
+type silentSender struct{}
+
+func (silentSender) SendCommand(ctx context.Context, command string) (capability.Response, error) {
+	return capability.Response{}, nil
+}
+
+func TestClientWithPlayerLister(t *testing.T) {
+	address := serveStatus(t, `{
+		"version": {"name": "1.20.4", "protocol": 765},
+		"players": {
+			"max": 20,
+			"online": 3,
+			"sample": [
+				{"name": "Notch", "id": "069a79f4-44e9-4726-a5be-fca90e38aaf5"},
+				{"name": "Anonymous Player", "id": "00000000-0000-0000-0000-000000000000"},
+				{"name": "jeb_", "id": "853c80ef-3c37-49fd-aa49-938b674adae6"}
+			]
+		},
+		"description": {"text": "A Minecraft Server"}
+	}`)
+	client := NewClient().WithCommandSender(silentSender{}).WithPlayerLister(Pinger{Address: address})
+
+	count, players, err := client.Players(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 3 {
+		t.Errorf("expected 3 players, got %d", count)
+	}
+	if len(players) != 2 || players[0] != "Notch" || players[1] != "jeb_" {
+		t.Errorf("expected the named players of the sample, got %v", players)
+	}
+}
+
+func TestClientWithoutOutput(t *testing.T) {
+	client := NewClient().WithCommandSender(silentSender{})
+
+	if _, err := client.CountPlayers(context.Background()); err == nil {
+		t.Error("expected counting players to fail without command output")
+	}
+}
@@ -0,0 +1,299 @@
+package minecraft
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// Properties of a server.properties file, keeping comments and order when edited
+type Properties struct {
+	lines []string
+}
+
+// ParseProperties from the content of a server.properties file
+func ParseProperties(content string) Properties {
+	return Properties{lines: strings.Split(strings.TrimRight(content, "\n"), "\n")}
+}
+
+// Get the value of key and whether it's set
+func (p Properties) Get(key string) (string, bool) {
+	for _, line := range p.lines {
+		if k, v, ok := parsePropertyLine(line); ok && k == key {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// Keys in the order of the file
+func (p Properties) Keys() []string {
+	keys := []string{}
+	for _, line := range p.lines {
+		if k, _, ok := parsePropertyLine(line); ok {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// Set key to value, appending it if it's not set yet
+func (p Properties) Set(key, value string) Properties {
+	lines := make([]string, len(p.lines))
+	copy(lines, p.lines)
+
+	line := escapeProperty(key, true) + "=" + escapeProperty(value, false)
+	for i, l := range lines {
+		if k, _, ok := parsePropertyLine(l); ok && k == key {
+			lines[i] = line
+			return Properties{lines: lines}
+		}
+	}
+	return Properties{lines: append(lines, line)}
+}
+
+// String returns the content of the server.properties file
+func (p Properties) String() string {
+	return strings.Join(p.lines, "\n") + "\n"
+}
+
+// parsePropertyLine into its unescaped key and value like java.util.Properties does,
+// except for values continued on the next line, which servers don't write
+func parsePropertyLine(line string) (string, string, bool) {
+	trimmed := strings.TrimLeft(strings.TrimRight(line, "\r"), " \t\f")
+	if len(trimmed) < 1 || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "!") {
+		return "", "", false
+	}
+
+	// the key ends at the first unescaped separator or whitespace
+	end := 0
+	for ; end < len(trimmed); end++ {
+		if trimmed[end] == '\\' {
+			end++
+			continue
+		}
+		if strings.IndexByte("=: \t\f", trimmed[end]) >= 0 {
+			break
+		}
+	}
+	if end > len(trimmed) {
+		end = len(trimmed)
+	}
+	key := unescapeProperty(trimmed[:end])
+
+	value := strings.TrimLeft(trimmed[end:], " \t\f")
+	if strings.HasPrefix(value, "=") || strings.HasPrefix(value, ":") {
+		value = strings.TrimLeft(value[1:], " \t\f")
+	}
+	return key, unescapeProperty(value), true
+}
+
+// escapeProperty for writing it to a properties file like java.util.Properties does.
+// Characters outside of ASCII are written as unicode escapes, so the file can be read
+// as ISO-8859-1 as well as UTF-8
+func escapeProperty(s string, key bool) string {
+	b := strings.Builder{}
+	for i, r := range s {
+		switch {
+		case r == ' ' && (key || i == 0):
+			b.WriteString(`\ `)
+		case r == '\\', r == '=', r == ':', r == '#', r == '!':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\f':
+			b.WriteString(`\f`)
+		case r < 0x20 || r > 0x7e:
+			for _, unit := range utf16.Encode([]rune{r}) {
+				fmt.Fprintf(&b, `\u%04X`, unit)
+			}
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// unescapeProperty read from a properties file
+func unescapeProperty(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	b := strings.Builder{}
+	var units []uint16
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+5 < len(s) && s[i+1] == 'u' {
+			if unit, err := strconv.ParseUint(s[i+2:i+6], 16, 16); err == nil {
+				units = append(units, uint16(unit))
+				i += 5
+				continue
+			}
+		}
+		// unicode escapes are collected first, as characters outside of the BMP are split in two
+		if len(units) > 0 {
+			b.WriteString(string(utf16.Decode(units)))
+			units = nil
+		}
+		if s[i] != '\\' || i+1 >= len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 'f':
+			b.WriteByte('\f')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	if len(units) > 0 {
+		b.WriteString(string(utf16.Decode(units)))
+	}
+	return b.String()
+}
+
+// propertyType validates the values of a property
+type propertyType struct {
+	kind     string
+	min, max int
+	values   []string
+}
+
+func (t propertyType) validate(value string) error {
+	switch t.kind {
+	case "bool":
+		if value != "true" && value != "false" {
+			return fmt.Errorf("expected true or false")
+		}
+	case "int":
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("expected a number")
+		}
+		if i < t.min || i > t.max {
+			return fmt.Errorf("expected a number between %d and %d", t.min, t.max)
+		}
+	case "enum":
+		for _, v := range t.values {
+			if v == value {
+				return nil
+			}
+		}
+		return fmt.Errorf("expected one of %s", strings.Join(t.values, ", "))
+	}
+	return nil
+}
+
+var (
+	boolProperty   = propertyType{kind: "bool"}
+	stringProperty = propertyType{kind: "string"}
+)
+
+func intProperty(min, max int) propertyType {
+	return propertyType{kind: "int", min: min, max: max}
+}
+
+func enumProperty(values ...string) propertyType {
+	return propertyType{kind: "enum", values: values}
+}
+
+// knownProperties of vanilla servers which can be set even if they're missing in the file
+var knownProperties = map[string]propertyType{
+	"allow-flight":                      boolProperty,
+	"allow-nether":                      boolProperty,
+	"difficulty":                        enumProperty("peaceful", "easy", "normal", "hard"),
+	"enable-command-block":              boolProperty,
+	"enable-status":                     boolProperty,
+	"enforce-whitelist":                 boolProperty,
+	"entity-broadcast-range-percentage": intProperty(10, 1000),
+	"force-gamemode":                    boolProperty,
+	"gamemode":                          enumProperty("survival", "creative", "adventure", "spectator"),
+	"hardcore":                          boolProperty,
+	"hide-online-players":               boolProperty,
+	"level-seed":                        stringProperty,
+	"max-players":                       intProperty(1, 1000),
+	"max-tick-time":                     intProperty(-1, 600000),
+	"max-world-size":                    intProperty(1, 29999984),
+	"motd":                              stringProperty,
+	"online-mode":                       boolProperty,
+	"player-idle-timeout":               intProperty(0, 1440),
+	"pvp":                               boolProperty,
+	"simulation-distance":               intProperty(3, 32),
+	"spawn-animals":                     boolProperty,
+	"spawn-monsters":                    boolProperty,
+	"spawn-npcs":                        boolProperty,
+	"spawn-protection":                  intProperty(0, 1000),
+	"view-distance":                     intProperty(3, 32),
+	"white-list":                        boolProperty,
+}
+
+// ValidateProperty checks key is either known or already set in p and value fits its type
+func ValidateProperty(p Properties, key, value string) error {
+	if strings.ContainsAny(value, "\r\n") {
+		return fmt.Errorf("invalid value for %s: must be a single line", key)
+	}
+	propertyType, known := knownProperties[key]
+	if !known {
+		if _, exists := p.Get(key); !exists {
+			return fmt.Errorf("unknown property %s", key)
+		}
+		propertyType = stringProperty
+	}
+	if err := propertyType.validate(value); err != nil {
+		return fmt.Errorf("invalid value for %s: %w", key, err)
+	}
+	return nil
+}
+
+// PropertiesFile stores server.properties in a file, e.g. on a volume shared with the server
+type PropertiesFile struct {
+	Path string
+}
+
+// Load the content of the file
+func (f PropertiesFile) Load(ctx context.Context) (string, error) {
+	content, err := os.ReadFile(f.Path)
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+// Save content by replacing the file
+func (f PropertiesFile) Save(ctx context.Context, content string) error {
+	info, err := os.Stat(f.Path)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(f.Path), ".server.properties-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.WriteString(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), info.Mode()); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.Path)
+}
@@ -0,0 +1,155 @@
+package minecraft
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// maxPacketLength limits the packets accepted from clients as the listener only
+// handles handshakes, status and login start packets
+const maxPacketLength = 1 << 12
+
+// maxStatusLength limits the status responses accepted from servers
+const maxStatusLength = 1 << 18
+
+var errVarIntTooLong = errors.New("varint too long")
+
+// readVarInt as defined by the Minecraft protocol
+func readVarInt(r io.ByteReader) (int32, error) {
+	var value uint32
+	for i := 0; i < 5; i++ {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		value |= uint32(b&0x7F) << (7 * i)
+		if b&0x80 == 0 {
+			return int32(value), nil
+		}
+	}
+	return 0, errVarIntTooLong
+}
+
+// writeVarInt as defined by the Minecraft protocol
+func writeVarInt(w *bytes.Buffer, value int32) {
+	v := uint32(value)
+	for {
+		if v&^0x7F == 0 {
+			w.WriteByte(byte(v))
+			return
+		}
+		w.WriteByte(byte(v&0x7F) | 0x80)
+		v >>= 7
+	}
+}
+
+func readString(r *bytes.Reader) (string, error) {
+	length, err := readVarInt(r)
+	if err != nil {
+		return "", err
+	}
+	if length < 0 || int(length) > r.Len() {
+		return "", fmt.Errorf("invalid string length %d", length)
+	}
+	buf := make([]byte, length)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
+
+func writeString(w *bytes.Buffer, s string) {
+	writeVarInt(w, int32(len(s)))
+	w.WriteString(s)
+}
+
+// packet received from or sent to a client
+type packet struct {
+	id   int32
+	data *bytes.Reader
+}
+
+// readPacket from an uncompressed connection
+func readPacket(r *bufio.Reader) (packet, error) {
+	return readPacketLimit(r, maxPacketLength)
+}
+
+// readPacketLimit from an uncompressed connection, accepting packets up to limit bytes
+func readPacketLimit(r *bufio.Reader, limit int32) (packet, error) {
+	length, err := readVarInt(r)
+	if err != nil {
+		return packet{}, err
+	}
+	if length < 1 || length > limit {
+		return packet{}, fmt.Errorf("invalid packet length %d", length)
+	}
+	buf := make([]byte, length)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return packet{}, err
+	}
+	data := bytes.NewReader(buf)
+	id, err := readVarInt(data)
+	if err != nil {
+		return packet{}, err
+	}
+	return packet{id: id, data: data}, nil
+}
+
+// writePacket with id and payload to w
+func writePacket(w io.Writer, id int32, payload []byte) error {
+	body := &bytes.Buffer{}
+	writeVarInt(body, id)
+	body.Write(payload)
+
+	framed := &bytes.Buffer{}
+	writeVarInt(framed, int32(body.Len()))
+	framed.Write(body.Bytes())
+
+	_, err := w.Write(framed.Bytes())
+	return err
+}
+
+// handshake sent by clients as first packet
+type handshake struct {
+	protocolVersion int32
+	address         string
+	port            uint16
+	nextState       int32
+}
+
+const (
+	stateStatus = 1
+	stateLogin  = 2
+)
+
+func parseHandshake(p packet) (handshake, error) {
+	if p.id != 0x00 {
+		return handshake{}, fmt.Errorf("unexpected handshake packet id %d", p.id)
+	}
+	version, err := readVarInt(p.data)
+	if err != nil {
+		return handshake{}, err
+	}
+	address, err := readString(p.data)
+	if err != nil {
+		return handshake{}, err
+	}
+	var port uint16
+	if err := binary.Read(p.data, binary.BigEndian, &port); err != nil {
+		return handshake{}, err
+	}
+	nextState, err := readVarInt(p.data)
+	if err != nil {
+		return handshake{}, err
+	}
+	return handshake{
+		protocolVersion: version,
+		address:         address,
+		port:            port,
+		nextState:       nextState,
+	}, nil
+}
@@ -9,6 +9,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/playnet-public/mc-bot/pkg/capability"
 	"github.com/seibert-media/golibs/log"
 	rcon "github.com/willroberts/minecraft-client"
 	"go.uber.org/zap"
@@ -48,19 +49,19 @@ func (c *ReconnectingRCON) Setup() error {
 }
 
 // SendCommand reconnecting the underlying session on any permanent connection errors
-func (c *ReconnectingRCON) SendCommand(ctx context.Context, command string) (rcon.Message, error) {
+func (c *ReconnectingRCON) SendCommand(ctx context.Context, command string) (capability.Response, error) {
 	ctx = log.WithFields(ctx, zap.String("command", command))
 
 	c.l.Lock()
 	defer c.l.Unlock()
 	if c.client == nil {
 		if err := c.Setup(); err != nil {
-			return rcon.Message{}, fmt.Errorf("failed to setup client: %w", err)
+			return capability.Response{}, fmt.Errorf("failed to setup client: %w", err)
 		}
 	}
 	msg, err := c.client.SendCommand(command)
 	if err == nil {
-		return msg, nil
+		return capability.Response{Body: msg.Body}, nil
 	}
 
 	log.From(ctx).Error("sending rcon command", zap.Error(err))
@@ -72,11 +73,11 @@ func (c *ReconnectingRCON) SendCommand(ctx context.Context, command string) (rco
 		errors.Is(err, io.ErrUnexpectedEOF) {
 		if err := c.Reconnect(ctx); err != nil {
 			log.From(ctx).Error("reconnecting rcon", zap.Error(err))
-			return rcon.Message{}, err
+			return capability.Response{}, err
 		}
 	}
 
-	return rcon.Message{}, nil
+	return capability.Response{}, nil
 }
 
 // Reconnect the session
@@ -0,0 +1,262 @@
+package minecraft
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/seibert-media/golibs/log"
+	"go.uber.org/zap"
+)
+
+const (
+	wakeListenerName = "minecraft-wake"
+
+	defaultSleepingMOTD = "Sleeping - join to wake"
+	defaultStartingText = "The server is starting, please retry in ~1 minute."
+)
+
+// WakeListener stands in for a scaled down server, answering status pings and
+// scaling the server up once a player tries to join
+type WakeListener struct {
+	// Address to listen on while the server is asleep, e.g. ":25565"
+	Address string
+	// Interval between checks if the server is asleep
+	Interval time.Duration
+	// MOTD shown in the server list while asleep
+	MOTD string
+	// ChannelID to announce wakeups in. Announcements are skipped if empty
+	ChannelID string
+
+	Scaler interface {
+		ScaleUp(ctx context.Context) error
+		Replicas(ctx context.Context) (int32, error)
+	}
+
+	waking *wakeState
+}
+
+// wakeState is shared between connections to avoid repeated scale ups
+type wakeState struct {
+	l        sync.Mutex
+	lastWake time.Time
+}
+
+// Name of the Runner
+func (w WakeListener) Name() string {
+	return wakeListenerName
+}
+
+// Run the listener until ctx is done, listening only while the server is scaled down
+func (w WakeListener) Run(ctx context.Context, session *discordgo.Session) error {
+	ctx = log.WithFields(ctx, zap.String("address", w.Address))
+	w.waking = &wakeState{}
+
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	var listener net.Listener
+	defer func() {
+		if listener != nil {
+			listener.Close()
+		}
+	}()
+
+	for {
+		replicas, err := w.Scaler.Replicas(ctx)
+		if err != nil {
+			log.From(ctx).Error("getting replicas", zap.Error(err))
+		}
+		switch {
+		case err != nil:
+		case replicas < 1 && listener == nil:
+			log.From(ctx).Info("server asleep, starting wake listener")
+			listener, err = net.Listen("tcp", w.Address)
+			if err != nil {
+				log.From(ctx).Error("starting wake listener", zap.Error(err))
+				listener = nil
+				break
+			}
+			go w.serve(ctx, session, listener)
+		case replicas > 0 && listener != nil:
+			log.From(ctx).Info("server awake, stopping wake listener")
+			listener.Close()
+			listener = nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w WakeListener) serve(ctx context.Context, session *discordgo.Session, listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return
+		}
+		if err != nil {
+			log.From(ctx).Error("accepting connection", zap.Error(err))
+			continue
+		}
+		go func() {
+			defer conn.Close()
+			ctx := log.WithFields(ctx, zap.String("remote", conn.RemoteAddr().String()))
+			if err := w.handle(ctx, session, conn); err != nil {
+				log.From(ctx).Debug("handling connection", zap.Error(err))
+			}
+		}()
+	}
+}
+
+func (w WakeListener) handle(ctx context.Context, session *discordgo.Session, conn net.Conn) error {
+	if err := conn.SetDeadline(time.Now().Add(10 * time.Second)); err != nil {
+		return err
+	}
+	r := bufio.NewReader(conn)
+
+	p, err := readPacket(r)
+	if err != nil {
+		return err
+	}
+	hs, err := parseHandshake(p)
+	if err != nil {
+		return err
+	}
+
+	switch hs.nextState {
+	case stateStatus:
+		return w.handleStatus(r, conn, hs)
+	case stateLogin:
+		return w.handleLogin(ctx, session, r, conn)
+	default:
+		return fmt.Errorf("unsupported next state %d", hs.nextState)
+	}
+}
+
+type statusResponse struct {
+	Version struct {
+		Name     string `json:"name"`
+		Protocol int32  `json:"protocol"`
+	} `json:"version"`
+	Players struct {
+		Max    int `json:"max"`
+		Online int `json:"online"`
+		// Sample of the online players, which vanilla servers limit to 12
+		Sample []struct {
+			Name string `json:"name"`
+			ID   string `json:"id"`
+		} `json:"sample,omitempty"`
+	} `json:"players"`
+	// Description is either a string or a chat component
+	Description interface{} `json:"description"`
+}
+
+type chatComponent struct {
+	Text string `json:"text"`
+}
+
+func (w WakeListener) handleStatus(r *bufio.Reader, conn net.Conn, hs handshake) error {
+	for {
+		p, err := readPacket(r)
+		if err != nil {
+			return err
+		}
+		switch p.id {
+		case 0x00:
+			motd := w.MOTD
+			if len(motd) < 1 {
+				motd = defaultSleepingMOTD
+			}
+			status := statusResponse{Description: chatComponent{Text: motd}}
+			status.Version.Name = "Sleeping"
+			// echo the client version so it doesn't show the server as incompatible
+			status.Version.Protocol = hs.protocolVersion
+			payload, err := json.Marshal(status)
+			if err != nil {
+				return err
+			}
+			buf := &bytes.Buffer{}
+			writeString(buf, string(payload))
+			if err := writePacket(conn, 0x00, buf.Bytes()); err != nil {
+				return err
+			}
+		case 0x01:
+			// ping carries a long which is echoed back as pong
+			payload := make([]byte, p.data.Len())
+			if _, err := p.data.Read(payload); err != nil {
+				return err
+			}
+			return writePacket(conn, 0x01, payload)
+		default:
+			return fmt.Errorf("unexpected status packet id %d", p.id)
+		}
+	}
+}
+
+func (w WakeListener) handleLogin(ctx context.Context, session *discordgo.Session, r *bufio.Reader, conn net.Conn) error {
+	p, err := readPacket(r)
+	if err != nil {
+		return err
+	}
+	if p.id != 0x00 {
+		return fmt.Errorf("unexpected login packet id %d", p.id)
+	}
+	player, err := readString(p.data)
+	if err != nil {
+		return err
+	}
+	ctx = log.WithFields(ctx, zap.String("player", player))
+
+	w.wake(ctx, session, player)
+
+	reason, err := json.Marshal(chatComponent{Text: defaultStartingText})
+	if err != nil {
+		return err
+	}
+	buf := &bytes.Buffer{}
+	writeString(buf, string(reason))
+	return writePacket(conn, 0x00, buf.Bytes())
+}
+
+// wakeDebounce prevents repeated scale ups from clients retrying to connect
+const wakeDebounce = 1 * time.Minute
+
+func (w WakeListener) wake(ctx context.Context, session *discordgo.Session, player string) {
+	// the lock is held while scaling up, so concurrent login attempts wait for the first one
+	// and only a successful scale up is debounced
+	w.waking.l.Lock()
+	if time.Since(w.waking.lastWake) < wakeDebounce {
+		w.waking.l.Unlock()
+		return
+	}
+
+	log.From(ctx).Info("waking up server on login attempt")
+	if err := w.Scaler.ScaleUp(ctx); err != nil {
+		w.waking.l.Unlock()
+		log.From(ctx).Error("scaling up server", zap.Error(err))
+		return
+	}
+	w.waking.lastWake = time.Now()
+	w.waking.l.Unlock()
+
+	if len(w.ChannelID) < 1 {
+		return
+	}
+	if _, err := session.ChannelMessageSendEmbed(w.ChannelID, &discordgo.MessageEmbed{
+		Title:       "Waking up Server",
+		Description: fmt.Sprintf("**%s** tried to join the sleeping server. Use /winddown to bring it down.", player),
+	}); err != nil {
+		log.From(ctx).Error("announcing wakeup", zap.Error(err))
+	}
+}
@@ -1,9 +0,0 @@
-package noop
-
-import "context"
-
-type MessageSender struct{}
-
-func (m MessageSender) SendMessage(_ context.Context, _ string) error {
-	return nil
-}
@@ -5,7 +5,7 @@ import (
 	"fmt"
 
 	"github.com/bwmarrin/discordgo"
-	"github.com/playnet-public/mc-bot/pkg/minecraft"
+	"github.com/playnet-public/mc-bot/pkg/capability"
 	"github.com/seibert-media/golibs/log"
 	"go.uber.org/zap"
 )
@@ -16,7 +16,7 @@ type Operand struct {
 	ChannelID string
 	RCONRole  string
 
-	CommandSender minecraft.CommandSender
+	CommandSender capability.CommandSender
 }
 
 const (
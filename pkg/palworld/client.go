@@ -0,0 +1,255 @@
+package palworld
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/seibert-media/golibs/log"
+	"go.uber.org/zap"
+)
+
+// DefaultShutdownDelay gives players time to finish what they are doing before restarts
+const DefaultShutdownDelay = 30 * time.Second
+
+// Client for the REST API of a Palworld dedicated server
+type Client struct {
+	apiURL        string
+	password      string
+	shutdownDelay time.Duration
+	httpClient    *http.Client
+}
+
+// NewClient for the REST API at apiURL, e.g. http://palworld:8212, authenticating
+// as admin with the AdminPassword of the server
+func NewClient(apiURL, password string) Client {
+	return Client{
+		apiURL:        strings.TrimSuffix(apiURL, "/"),
+		password:      password,
+		shutdownDelay: DefaultShutdownDelay,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// WithShutdownDelay returns a Client announcing restarts delay ahead of shutting down
+func (c Client) WithShutdownDelay(delay time.Duration) Client {
+	c.shutdownDelay = delay
+	return c
+}
+
+// Info about the server
+type Info struct {
+	Version     string
+	Name        string
+	Description string
+	WorldGUID   string
+}
+
+// Info about the server
+func (c Client) Info(ctx context.Context) (Info, error) {
+	var resp struct {
+		Version     string `json:"version"`
+		ServerName  string `json:"servername"`
+		Description string `json:"description"`
+		WorldGUID   string `json:"worldguid"`
+	}
+	if err := c.get(ctx, "info", &resp); err != nil {
+		return Info{}, err
+	}
+	return Info{
+		Version:     resp.Version,
+		Name:        resp.ServerName,
+		Description: resp.Description,
+		WorldGUID:   resp.WorldGUID,
+	}, nil
+}
+
+// Version of the server
+func (c Client) Version(ctx context.Context) (string, error) {
+	info, err := c.Info(ctx)
+	if err != nil {
+		return "", err
+	}
+	return info.Version, nil
+}
+
+// Metrics of the server
+type Metrics struct {
+	FPS        int
+	FrameTime  time.Duration
+	Players    int
+	MaxPlayers int
+	Uptime     time.Duration
+	Days       int
+}
+
+// Metrics of the server
+func (c Client) Metrics(ctx context.Context) (Metrics, error) {
+	var resp struct {
+		ServerFPS        int     `json:"serverfps"`
+		ServerFrameTime  float64 `json:"serverframetime"`
+		CurrentPlayerNum int     `json:"currentplayernum"`
+		MaxPlayerNum     int     `json:"maxplayernum"`
+		Uptime           int64   `json:"uptime"`
+		Days             int     `json:"days"`
+	}
+	if err := c.get(ctx, "metrics", &resp); err != nil {
+		return Metrics{}, err
+	}
+	return Metrics{
+		FPS:        resp.ServerFPS,
+		FrameTime:  time.Duration(resp.ServerFrameTime * float64(time.Millisecond)),
+		Players:    resp.CurrentPlayerNum,
+		MaxPlayers: resp.MaxPlayerNum,
+		Uptime:     time.Duration(resp.Uptime) * time.Second,
+		Days:       resp.Days,
+	}, nil
+}
+
+// CountPlayers on the server right now
+func (c Client) CountPlayers(ctx context.Context) (int, error) {
+	metrics, err := c.Metrics(ctx)
+	if err != nil {
+		return -1, err
+	}
+	return metrics.Players, nil
+}
+
+// Players on the server right now
+func (c Client) Players(ctx context.Context) (int, []string, error) {
+	var resp struct {
+		Players []struct {
+			Name string `json:"name"`
+		} `json:"players"`
+	}
+	if err := c.get(ctx, "players", &resp); err != nil {
+		return -1, nil, err
+	}
+
+	players := make([]string, 0, len(resp.Players))
+	for _, player := range resp.Players {
+		players = append(players, player.Name)
+	}
+	return len(players), players, nil
+}
+
+// SendMessage to all players on the server
+func (c Client) SendMessage(ctx context.Context, msg string) error {
+	return c.post(ctx, "announce", map[string]interface{}{"message": msg})
+}
+
+// Save the world
+func (c Client) Save(ctx context.Context) error {
+	return c.post(ctx, "save", nil)
+}
+
+// Shutdown the server after delay, announcing msg to the players
+func (c Client) Shutdown(ctx context.Context, delay time.Duration, msg string) error {
+	return c.post(ctx, "shutdown", map[string]interface{}{
+		"waittime": int(delay.Seconds()),
+		"message":  msg,
+	})
+}
+
+// Restart the server gracefully by saving the world and shutting it down after announcing it.
+// The server does not start again on its own, so it relies on the restart policy of its container
+func (c Client) Restart(ctx context.Context) error {
+	if err := c.Save(ctx); err != nil {
+		log.From(ctx).Error("saving before restart", zap.Error(err))
+	}
+	return c.Shutdown(ctx, c.shutdownDelay, fmt.Sprintf("The server restarts in %s", c.shutdownDelay))
+}
+
+// BackendRestarter restarts the server using a backend, e.g. a Kubernetes workload, which stops
+// the server right away. Like Client.Restart it announces the restart shutdown delay ahead
+type BackendRestarter struct {
+	Client  Client
+	Backend interface {
+		Restart(ctx context.Context) error
+	}
+}
+
+// Restart announces the restart and returns, saving the world and restarting the server through
+// the backend once the shutdown delay has passed. Servers not receiving the announcement are
+// restarted right away, as they likely don't respond anymore
+func (r BackendRestarter) Restart(ctx context.Context) error {
+	delay := r.Client.shutdownDelay
+	if err := r.Client.SendMessage(ctx, fmt.Sprintf("The server restarts in %s", delay)); err != nil {
+		log.From(ctx).Error("announcing restart", zap.Error(err))
+		return r.Backend.Restart(ctx)
+	}
+
+	time.AfterFunc(delay, func() {
+		if err := r.Client.Save(ctx); err != nil {
+			log.From(ctx).Error("saving before restart", zap.Error(err))
+		}
+		if err := r.Backend.Restart(ctx); err != nil {
+			log.From(ctx).Error("restarting server", zap.Error(err))
+		}
+	})
+	return nil
+}
+
+func (c Client) post(ctx context.Context, endpoint string, body interface{}) error {
+	var payload io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		payload = bytes.NewReader(raw)
+	}
+	resp, err := c.do(ctx, http.MethodPost, endpoint, payload)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errorFrom(resp)
+	}
+	return nil
+}
+
+func (c Client) get(ctx context.Context, endpoint string, into interface{}) error {
+	resp, err := c.do(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errorFrom(resp)
+	}
+	return json.NewDecoder(resp.Body).Decode(into)
+}
+
+func (c Client) do(ctx context.Context, method string, endpoint string, body io.Reader) (*http.Response, error) {
+	url := fmt.Sprintf("%s/v1/api/%s", c.apiURL, endpoint)
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth("admin", c.password)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return c.httpClient.Do(req)
+}
+
+// errorFrom builds an error from the body of a failed response, which is plain text
+func errorFrom(resp *http.Response) error {
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+	if err != nil || len(strings.TrimSpace(string(raw))) < 1 {
+		return fmt.Errorf("palworld responded with %s", resp.Status)
+	}
+	return fmt.Errorf("palworld responded with %s: %s", resp.Status, strings.TrimSpace(string(raw)))
+}
@@ -0,0 +1,85 @@
+package process
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/playnet-public/mc-bot/pkg/capability"
+)
+
+// maxLogLines kept of the output of each process
+const maxLogLines = 2000
+
+type logLine struct {
+	at   time.Time
+	text string
+}
+
+// logBuffer keeps the last maxLogLines output lines of a process
+type logBuffer struct {
+	l     sync.Mutex
+	lines []logLine
+	// next is the index overwritten by the next line once the buffer is full
+	next int
+}
+
+func (b *logBuffer) add(text string) {
+	b.l.Lock()
+	defer b.l.Unlock()
+	line := logLine{at: time.Now(), text: text}
+	if len(b.lines) < maxLogLines {
+		b.lines = append(b.lines, line)
+		return
+	}
+	b.lines[b.next] = line
+	b.next = (b.next + 1) % maxLogLines
+}
+
+// tail returns the last lines, all if lines is not positive, limited to the ones newer than since if set
+func (b *logBuffer) tail(lines int64, since time.Duration) []string {
+	b.l.Lock()
+	defer b.l.Unlock()
+	ordered := append(append([]logLine{}, b.lines[b.next:]...), b.lines[:b.next]...)
+	if since > 0 {
+		after := time.Now().Add(-since)
+		first := len(ordered)
+		for i, line := range ordered {
+			if line.at.After(after) {
+				first = i
+				break
+			}
+		}
+		ordered = ordered[first:]
+	}
+	if lines > 0 && int64(len(ordered)) > lines {
+		ordered = ordered[int64(len(ordered))-lines:]
+	}
+
+	texts := make([]string, 0, len(ordered))
+	for _, line := range ordered {
+		texts = append(texts, line.text)
+	}
+	return texts
+}
+
+// Logs of the server process, which are kept for the running and the previous process.
+// The name of the Supervisor is returned as source of the logs
+func (s *Supervisor) Logs(ctx context.Context, opts capability.LogOptions) (string, string, error) {
+	s.l.Lock()
+	logs := s.logs
+	if opts.Previous {
+		logs = s.previousLogs
+	}
+	s.l.Unlock()
+	if logs == nil {
+		return name, "", ErrNotRunning
+	}
+
+	lines := logs.tail(opts.Lines, opts.Since)
+	if len(lines) < 1 {
+		return name, "", nil
+	}
+	return name, strings.Join(lines, "\n") + "\n", nil
+}
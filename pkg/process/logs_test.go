@@ -0,0 +1,72 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/playnet-public/mc-bot/pkg/capability"
+)
+
+func TestLogBufferTail(t *testing.T) {
+	b := &logBuffer{}
+	for i := 0; i < maxLogLines+5; i++ {
+		b.add(fmt.Sprintf("line %d", i))
+	}
+
+	lines := b.tail(0, 0)
+	if len(lines) != maxLogLines {
+		t.Fatalf("expected the buffer to keep %d lines, got %d", maxLogLines, len(lines))
+	}
+	if lines[0] != "line 5" || lines[len(lines)-1] != fmt.Sprintf("line %d", maxLogLines+4) {
+		t.Errorf("expected the oldest lines to be dropped, got %q to %q", lines[0], lines[len(lines)-1])
+	}
+
+	expected := []string{fmt.Sprintf("line %d", maxLogLines+3), fmt.Sprintf("line %d", maxLogLines+4)}
+	if lines := b.tail(2, 0); !reflect.DeepEqual(lines, expected) {
+		t.Errorf("expected %v, got %v", expected, lines)
+	}
+}
+
+func TestLogBufferTailSince(t *testing.T) {
+	b := &logBuffer{}
+	b.add("old")
+	b.lines[0].at = time.Now().Add(-time.Hour)
+	b.add("new")
+
+	if lines := b.tail(0, time.Minute); !reflect.DeepEqual(lines, []string{"new"}) {
+		t.Errorf("expected only the new line, got %v", lines)
+	}
+	if lines := b.tail(0, 2*time.Hour); !reflect.DeepEqual(lines, []string{"old", "new"}) {
+		t.Errorf("expected all lines, got %v", lines)
+	}
+}
+
+func TestSupervisorLogs(t *testing.T) {
+	s := NewSupervisor([]string{"sh", "-c", "echo started; echo stopping"}, "", "stop", time.Second)
+	if _, _, err := s.Logs(context.Background(), capability.LogOptions{}); err != ErrNotRunning {
+		t.Errorf("expected no logs before the first start, got %v", err)
+	}
+
+	for _, run := range []string{"first", "second"} {
+		if err := s.Start(context.Background()); err != nil {
+			t.Fatalf("starting %s process: %v", run, err)
+		}
+		s.l.Lock()
+		done := s.done
+		s.l.Unlock()
+		<-done
+	}
+
+	for _, previous := range []bool{false, true} {
+		source, logs, err := s.Logs(context.Background(), capability.LogOptions{Previous: previous})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if source != name || logs != "started\nstopping\n" {
+			t.Errorf("expected the output of the process, got %s %q", source, logs)
+		}
+	}
+}
@@ -0,0 +1,308 @@
+package process
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/playnet-public/mc-bot/pkg/capability"
+	"github.com/seibert-media/golibs/log"
+	"go.uber.org/zap"
+)
+
+const (
+	name = "process"
+
+	// responseWindow is the time output is collected for after sending a command
+	responseWindow = 500 * time.Millisecond
+	// maxLineLength of the output, longer lines stop the output from being read
+	maxLineLength = 1024 * 1024
+)
+
+// ErrNotRunning indicates the server process is not running
+var ErrNotRunning = errors.New("server process is not running")
+
+// Supervisor runs a game server as child process, using its stdin as console
+// and keeping the last lines of its output as logs
+type Supervisor struct {
+	command     []string
+	dir         string
+	stopCommand string
+	killTimeout time.Duration
+	// responsePattern matches the output lines considered responses to commands
+	responsePattern *regexp.Regexp
+
+	// commandL serializes commands, so their responses don't mix
+	commandL sync.Mutex
+
+	l     sync.Mutex
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+	done  chan struct{}
+	// logs of the running or last process and previousLogs of the one before
+	logs         *logBuffer
+	previousLogs *logBuffer
+
+	subscribersL sync.Mutex
+	subscribers  map[chan string]struct{}
+}
+
+// NewSupervisor for command running in dir. The server is stopped by sending
+// stopCommand to its console and killed if it's still running after killTimeout
+func NewSupervisor(command []string, dir string, stopCommand string, killTimeout time.Duration) *Supervisor {
+	return &Supervisor{
+		command:     command,
+		dir:         dir,
+		stopCommand: stopCommand,
+		killTimeout: killTimeout,
+		subscribers: make(map[chan string]struct{}),
+	}
+}
+
+// WithResponsePattern only considers output lines matching pattern as responses to commands,
+// e.g. to skip log lines of other threads. The first non-empty group of pattern is used as
+// response if it has any, which allows to strip log prefixes. Must be called before Run
+func (s *Supervisor) WithResponsePattern(pattern *regexp.Regexp) *Supervisor {
+	s.responsePattern = pattern
+	return s
+}
+
+// Name of the Runner
+func (s *Supervisor) Name() string {
+	return name
+}
+
+// Run starts the server process and stops it once ctx is done
+func (s *Supervisor) Run(ctx context.Context, session *discordgo.Session) error {
+	if err := s.Start(ctx); err != nil {
+		return err
+	}
+	<-ctx.Done()
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), s.killTimeout+5*time.Second)
+	defer cancel()
+	return s.Stop(stopCtx)
+}
+
+// Start the server process if it's not running yet
+func (s *Supervisor) Start(ctx context.Context) error {
+	s.l.Lock()
+	defer s.l.Unlock()
+	if s.running() {
+		return nil
+	}
+	if len(s.command) < 1 {
+		return errors.New("no command configured")
+	}
+
+	cmd := exec.Command(s.command[0], s.command[1:]...)
+	cmd.Dir = s.dir
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	cmd.Stderr = cmd.Stdout
+
+	log.From(ctx).Info("starting server process", zap.Strings("command", s.command))
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	logs := &logBuffer{}
+	s.cmd = cmd
+	s.stdin = stdin
+	s.done = done
+	s.previousLogs = s.logs
+	s.logs = logs
+
+	// the context of the request starting the process must not end the output handling
+	logCtx := log.WithLogger(context.Background(), log.From(ctx))
+	go func() {
+		// all output has to be read before waiting for the process
+		s.readOutput(logCtx, stdout, logs)
+		err := cmd.Wait()
+		log.From(logCtx).Info("server process exited", zap.Error(err))
+		close(done)
+	}()
+
+	return nil
+}
+
+// Stop the server process gracefully, killing it after the kill timeout
+func (s *Supervisor) Stop(ctx context.Context) error {
+	s.l.Lock()
+	if !s.running() {
+		s.l.Unlock()
+		return nil
+	}
+	log.From(ctx).Info("stopping server process")
+	if _, err := io.WriteString(s.stdin, s.stopCommand+"\n"); err != nil {
+		log.From(ctx).Error("sending stop command", zap.Error(err))
+	}
+	// the lock is released while waiting, so the state can be queried in the meantime
+	process, done := s.cmd.Process, s.done
+	s.l.Unlock()
+
+	select {
+	case <-done:
+		return nil
+	case <-time.After(s.killTimeout):
+		log.From(ctx).Warn("killing server process", zap.Duration("timeout", s.killTimeout))
+	case <-ctx.Done():
+		log.From(ctx).Warn("killing server process", zap.Error(ctx.Err()))
+	}
+
+	if err := process.Kill(); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		return err
+	}
+	<-done
+	return nil
+}
+
+// Restart the server process
+func (s *Supervisor) Restart(ctx context.Context) error {
+	if err := s.Stop(ctx); err != nil {
+		return err
+	}
+	return s.Start(ctx)
+}
+
+// ScaleUp starts the server process
+func (s *Supervisor) ScaleUp(ctx context.Context) error {
+	return s.Start(ctx)
+}
+
+// ScaleDown stops the server process
+func (s *Supervisor) ScaleDown(ctx context.Context) error {
+	return s.Stop(ctx)
+}
+
+// Replicas returns 1 if the server process is running and 0 otherwise
+func (s *Supervisor) Replicas(ctx context.Context) (int32, error) {
+	s.l.Lock()
+	defer s.l.Unlock()
+	if s.running() {
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// SendCommand to the server console, returning the response lines printed shortly after.
+// Commands are sent one at a time, so each one only gets its own response
+func (s *Supervisor) SendCommand(ctx context.Context, command string) (capability.Response, error) {
+	s.commandL.Lock()
+	defer s.commandL.Unlock()
+
+	lines, unsubscribe := s.Subscribe()
+	defer unsubscribe()
+
+	s.l.Lock()
+	if !s.running() {
+		s.l.Unlock()
+		return capability.Response{}, ErrNotRunning
+	}
+	_, err := io.WriteString(s.stdin, command+"\n")
+	s.l.Unlock()
+	if err != nil {
+		return capability.Response{}, fmt.Errorf("writing command: %w", err)
+	}
+
+	var output []string
+	timeout := time.After(responseWindow)
+	for {
+		select {
+		case line := <-lines:
+			if response, ok := s.response(line); ok {
+				output = append(output, response)
+			}
+		case <-timeout:
+			return capability.Response{Body: strings.Join(output, "\n")}, nil
+		case <-ctx.Done():
+			return capability.Response{}, ctx.Err()
+		}
+	}
+}
+
+// Subscribe to the output lines of the server process. Lines are dropped for
+// subscribers not keeping up
+func (s *Supervisor) Subscribe() (<-chan string, func()) {
+	lines := make(chan string, 64)
+
+	s.subscribersL.Lock()
+	s.subscribers[lines] = struct{}{}
+	s.subscribersL.Unlock()
+
+	return lines, func() {
+		s.subscribersL.Lock()
+		delete(s.subscribers, lines)
+		s.subscribersL.Unlock()
+	}
+}
+
+// response returns the part of line responding to a command, if it's a response at all
+func (s *Supervisor) response(line string) (string, bool) {
+	if s.responsePattern == nil {
+		return line, true
+	}
+	match := s.responsePattern.FindStringSubmatch(line)
+	if match == nil {
+		return "", false
+	}
+	for _, group := range match[1:] {
+		if len(group) > 0 {
+			return group, true
+		}
+	}
+	return match[0], true
+}
+
+func (s *Supervisor) readOutput(ctx context.Context, output io.Reader, logs *logBuffer) {
+	scanner := bufio.NewScanner(output)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineLength)
+	for scanner.Scan() {
+		line := scanner.Text()
+		log.From(ctx).Debug("server output", zap.String("line", line))
+		logs.add(line)
+
+		s.subscribersL.Lock()
+		for subscriber := range s.subscribers {
+			select {
+			case subscriber <- line:
+			default:
+			}
+		}
+		s.subscribersL.Unlock()
+	}
+	if err := scanner.Err(); err != nil {
+		log.From(ctx).Error("reading server output", zap.Error(err))
+		// the output is still drained, so the process doesn't block on writing it
+		_, _ = io.Copy(io.Discard, output)
+	}
+}
+
+// running must be called with s.l held
+func (s *Supervisor) running() bool {
+	if s.done == nil {
+		return false
+	}
+	select {
+	case <-s.done:
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,192 @@
+package pterodactyl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/playnet-public/mc-bot/pkg/capability"
+	"github.com/seibert-media/golibs/log"
+	"go.uber.org/zap"
+)
+
+// Client for the Pterodactyl client API managing a single server
+type Client struct {
+	panelURL       string
+	apiKey         string
+	serverID       string
+	messageCommand string
+	httpClient     *http.Client
+}
+
+// NewClient for the server with serverID on the panel at panelURL.
+// messageCommand is prefixed to messages sent to the server, e.g. "say"
+func NewClient(panelURL, apiKey, serverID, messageCommand string) Client {
+	return Client{
+		panelURL:       strings.TrimSuffix(panelURL, "/"),
+		apiKey:         apiKey,
+		serverID:       serverID,
+		messageCommand: messageCommand,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// Power signals accepted by the panel
+const (
+	signalStart   = "start"
+	signalStop    = "stop"
+	signalRestart = "restart"
+)
+
+// Restart the server
+func (c Client) Restart(ctx context.Context) error {
+	return c.power(ctx, signalRestart)
+}
+
+// ScaleUp starts the server
+func (c Client) ScaleUp(ctx context.Context) error {
+	return c.power(ctx, signalStart)
+}
+
+// ScaleDown stops the server
+func (c Client) ScaleDown(ctx context.Context) error {
+	return c.power(ctx, signalStop)
+}
+
+// Replicas returns 1 if the server is running and 0 otherwise
+func (c Client) Replicas(ctx context.Context) (int32, error) {
+	resources, err := c.Resources(ctx)
+	if err != nil {
+		return -1, err
+	}
+	if resources.State == "running" {
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// SendCommand to the server console. The panel does not return the command
+// output, so the returned message is always empty
+func (c Client) SendCommand(ctx context.Context, command string) (capability.Response, error) {
+	if err := c.post(ctx, "command", map[string]string{"command": command}); err != nil {
+		return capability.Response{}, err
+	}
+	return capability.Response{}, nil
+}
+
+// SendMessage to the players on the server using the message command
+func (c Client) SendMessage(ctx context.Context, msg string) error {
+	_, err := c.SendCommand(ctx, c.messageCommand+" "+msg)
+	return err
+}
+
+// Resources used by the server
+type Resources struct {
+	State            string
+	MemoryBytes      int64
+	MemoryLimitBytes int64
+	CPUPercent       float64
+	DiskBytes        int64
+	Uptime           time.Duration
+}
+
+// Resources returns the current state and resource usage of the server
+func (c Client) Resources(ctx context.Context) (Resources, error) {
+	var resp struct {
+		Attributes struct {
+			CurrentState string `json:"current_state"`
+			Resources    struct {
+				MemoryBytes      int64   `json:"memory_bytes"`
+				MemoryLimitBytes int64   `json:"memory_limit_bytes"`
+				CPUAbsolute      float64 `json:"cpu_absolute"`
+				DiskBytes        int64   `json:"disk_bytes"`
+				Uptime           int64   `json:"uptime"`
+			} `json:"resources"`
+		} `json:"attributes"`
+	}
+	if err := c.get(ctx, "resources", &resp); err != nil {
+		return Resources{}, err
+	}
+
+	log.From(ctx).Debug("receiving resources response", zap.String("state", resp.Attributes.CurrentState))
+
+	return Resources{
+		State:            resp.Attributes.CurrentState,
+		MemoryBytes:      resp.Attributes.Resources.MemoryBytes,
+		MemoryLimitBytes: resp.Attributes.Resources.MemoryLimitBytes,
+		CPUPercent:       resp.Attributes.Resources.CPUAbsolute,
+		DiskBytes:        resp.Attributes.Resources.DiskBytes,
+		Uptime:           time.Duration(resp.Attributes.Resources.Uptime) * time.Millisecond,
+	}, nil
+}
+
+func (c Client) power(ctx context.Context, signal string) error {
+	return c.post(ctx, "power", map[string]string{"signal": signal})
+}
+
+func (c Client) post(ctx context.Context, endpoint string, body interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	resp, err := c.do(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return errorFrom(resp)
+	}
+	return nil
+}
+
+func (c Client) get(ctx context.Context, endpoint string, into interface{}) error {
+	resp, err := c.do(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errorFrom(resp)
+	}
+	return json.NewDecoder(resp.Body).Decode(into)
+}
+
+func (c Client) do(ctx context.Context, method string, endpoint string, body io.Reader) (*http.Response, error) {
+	url := fmt.Sprintf("%s/api/client/servers/%s/%s", c.panelURL, c.serverID, endpoint)
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	return c.httpClient.Do(req)
+}
+
+// errorFrom builds an error from the details of a failed response
+func errorFrom(resp *http.Response) error {
+	var body struct {
+		Errors []struct {
+			Code   string `json:"code"`
+			Detail string `json:"detail"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&body); err != nil || len(body.Errors) < 1 {
+		return fmt.Errorf("pterodactyl responded with %s", resp.Status)
+	}
+	details := make([]string, 0, len(body.Errors))
+	for _, e := range body.Errors {
+		details = append(details, e.Detail)
+	}
+	return fmt.Errorf("pterodactyl responded with %s: %s", resp.Status, strings.Join(details, ", "))
+}
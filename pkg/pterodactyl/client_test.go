@@ -0,0 +1,132 @@
+package pterodactyl
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type request struct {
+	method string
+	path   string
+	body   map[string]string
+}
+
+// newPanel serves handler for the panel API, returning the client and the received requests
+func newPanel(t *testing.T, handler http.HandlerFunc) (Client, *[]request) {
+	requests := &[]request{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		received := request{method: r.Method, path: r.URL.Path}
+		if r.Method == http.MethodPost {
+			if err := json.NewDecoder(r.Body).Decode(&received.body); err != nil {
+				t.Errorf("decoding request body: %v", err)
+			}
+		}
+		*requests = append(*requests, received)
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return NewClient(server.URL+"/", "secret", "1a2b3c4d", "say"), requests
+}
+
+func noContent(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func TestClientPower(t *testing.T) {
+	cases := []struct {
+		signal string
+		action func(c Client) error
+	}{
+		{signal: "start", action: func(c Client) error { return c.ScaleUp(context.Background()) }},
+		{signal: "stop", action: func(c Client) error { return c.ScaleDown(context.Background()) }},
+		{signal: "restart", action: func(c Client) error { return c.Restart(context.Background()) }},
+	}
+	for _, c := range cases {
+		t.Run(c.signal, func(t *testing.T) {
+			client, requests := newPanel(t, noContent)
+
+			if err := c.action(client); err != nil {
+				t.Fatal(err)
+			}
+			received := (*requests)[0]
+			if received.path != "/api/client/servers/1a2b3c4d/power" || received.body["signal"] != c.signal {
+				t.Errorf("expected power signal %s, got %s %v", c.signal, received.path, received.body)
+			}
+		})
+	}
+}
+
+func TestClientSendMessage(t *testing.T) {
+	client, requests := newPanel(t, noContent)
+
+	if err := client.SendMessage(context.Background(), "Restarting soon"); err != nil {
+		t.Fatal(err)
+	}
+	received := (*requests)[0]
+	if received.path != "/api/client/servers/1a2b3c4d/command" || received.body["command"] != "say Restarting soon" {
+		t.Errorf("expected message to be sent as command, got %s %v", received.path, received.body)
+	}
+}
+
+func TestClientResources(t *testing.T) {
+	client, _ := newPanel(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{
+			"object": "stats",
+			"attributes": {
+				"current_state": "running",
+				"resources": {
+					"memory_bytes": 1024,
+					"memory_limit_bytes": 4096,
+					"cpu_absolute": 12.5,
+					"disk_bytes": 2048,
+					"uptime": 90000
+				}
+			}
+		}`))
+	})
+
+	resources, err := client.Resources(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Resources{
+		State:            "running",
+		MemoryBytes:      1024,
+		MemoryLimitBytes: 4096,
+		CPUPercent:       12.5,
+		DiskBytes:        2048,
+		Uptime:           90 * time.Second,
+	}
+	if resources != expected {
+		t.Errorf("expected %+v, got %+v", expected, resources)
+	}
+
+	replicas, err := client.Replicas(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replicas != 1 {
+		t.Errorf("expected a running server to have 1 replica, got %d", replicas)
+	}
+}
+
+func TestClientError(t *testing.T) {
+	client, _ := newPanel(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		_, _ = w.Write([]byte(`{"errors":[{"code":"ConflictHttpException","detail":"Server is installing"}]}`))
+	})
+
+	err := client.ScaleUp(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "Server is installing") {
+		t.Errorf("expected the panel's error details, got %v", err)
+	}
+}
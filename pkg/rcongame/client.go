@@ -0,0 +1,159 @@
+package rcongame
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/playnet-public/mc-bot/pkg/capability"
+	"github.com/playnet-public/mc-bot/pkg/minecraft"
+	"github.com/seibert-media/golibs/log"
+	"go.uber.org/zap"
+)
+
+// ErrUnsupported indicates the game has no template for a command
+var ErrUnsupported = errors.New("command not supported by this game")
+
+// Client for any game speaking Source RCON, using Templates for its commands
+type Client struct {
+	rcon      minecraft.CommandSender
+	templates Templates
+}
+
+// NewClient using templates for the commands of the game
+func NewClient(templates Templates) Client {
+	return Client{
+		templates: templates,
+	}
+}
+
+// Setup brings the Client into a functional state by starting a RCON session
+// with the provided credentials
+func (c Client) Setup(address string, password string) (Client, error) {
+	if err := c.templates.Validate(); err != nil {
+		return c, err
+	}
+	rcon := minecraft.NewReconnectingRCON(address, password)
+
+	c.rcon = rcon
+	if err := rcon.Setup(); err != nil {
+		return c, err
+	}
+
+	return c, nil
+}
+
+// Templates of the client
+func (c Client) Templates() Templates {
+	return c.templates
+}
+
+// Supports returns if the templates have a command for the requested capability
+func (c Client) Supports(requested capability.Capability) bool {
+	switch requested {
+	case capability.Messages:
+		return len(c.templates.Say) > 0
+	case capability.Whitelist:
+		return len(c.templates.Whitelist) > 0
+	case capability.Kick:
+		return len(c.templates.Kick) > 0
+	case capability.Restart:
+		return len(c.templates.Restart) > 0
+	}
+	return true
+}
+
+// SendCommand to the server via RCON
+func (c Client) SendCommand(ctx context.Context, command string) (capability.Response, error) {
+	msg, err := c.rcon.SendCommand(ctx, command)
+	if err != nil {
+		return capability.Response{}, err
+	}
+
+	log.From(ctx).Info("receiving sendCommand response", zap.String("payload", msg.Body))
+
+	return msg, nil
+}
+
+// SendMessage to the players on the server
+func (c Client) SendMessage(ctx context.Context, msg string) error {
+	_, err := c.send(ctx, "say", c.templates.Say, map[string]string{PlaceholderMessage: msg})
+	return err
+}
+
+// Kick player from the server
+func (c Client) Kick(ctx context.Context, player string) error {
+	_, err := c.send(ctx, "kick", c.templates.Kick, map[string]string{PlaceholderPlayer: player})
+	return err
+}
+
+// Save the world
+func (c Client) Save(ctx context.Context) error {
+	_, err := c.send(ctx, "save", c.templates.Save, nil)
+	return err
+}
+
+// Whitelist the provided username
+func (c Client) Whitelist(ctx context.Context, username string) error {
+	_, err := c.send(ctx, "whitelist", c.templates.Whitelist, map[string]string{PlaceholderPlayer: username})
+	return err
+}
+
+// Restart the server via RCON
+func (c Client) Restart(ctx context.Context) error {
+	_, err := c.send(ctx, "restart", c.templates.Restart, nil)
+	return err
+}
+
+// CountPlayers returns the number of players returned by the list command
+func (c Client) CountPlayers(ctx context.Context) (int, error) {
+	playerCount, _, err := c.Players(ctx)
+	return playerCount, err
+}
+
+// Players returns the number of players and their names as returned by the list command
+func (c Client) Players(ctx context.Context) (int, []string, error) {
+	msg, err := c.send(ctx, "list", c.templates.List, nil)
+	if err != nil {
+		return -1, nil, err
+	}
+
+	nameIndex := c.templates.Player.SubexpIndex("name")
+	players := []string{}
+	for _, match := range c.templates.Player.FindAllStringSubmatch(msg.Body, -1) {
+		if name := strings.TrimSpace(match[nameIndex]); len(name) > 0 {
+			players = append(players, name)
+		}
+	}
+
+	if c.templates.PlayerCount == nil {
+		return len(players), players, nil
+	}
+	match := c.templates.PlayerCount.FindStringSubmatch(msg.Body)
+	if match == nil {
+		return -1, nil, fmt.Errorf("invalid player list response: %s", msg.Body)
+	}
+	count := match[c.templates.PlayerCount.SubexpIndex("count")]
+	playerCount, err := strconv.Atoi(count)
+	if err != nil {
+		return -1, nil, fmt.Errorf("invalid player count %s: %w", count, err)
+	}
+	return playerCount, players, nil
+}
+
+// send the command rendered from template, which is named for logging
+func (c Client) send(ctx context.Context, name string, template string, values map[string]string) (capability.Response, error) {
+	if len(template) < 1 {
+		return capability.Response{}, fmt.Errorf("%s: %w", name, ErrUnsupported)
+	}
+	msg, err := c.rcon.SendCommand(ctx, render(template, values))
+	if err != nil {
+		return capability.Response{}, err
+	}
+
+	log.From(ctx).Info("receiving rcon response", zap.String("command", name), zap.String("payload", msg.Body))
+
+	return msg, nil
+}
@@ -0,0 +1,105 @@
+package rcongame
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Placeholders replaced in command templates
+const (
+	PlaceholderMessage = "{message}"
+	PlaceholderPlayer  = "{player}"
+)
+
+// Templates of the commands and response parsers of a game.
+// Commands left empty are not supported by the game
+type Templates struct {
+	// List returns the players on the server
+	List string
+	// Say sends {message} to the players
+	Say string
+	// Kick removes {player} from the server
+	Kick string
+	// Save persists the world
+	Save string
+	// Whitelist allows {player} to join
+	Whitelist string
+	// Restart the server
+	Restart string
+
+	// PlayerCount is matched against the List response and requires a named group "count".
+	// The number of players matched by Player is used if unset
+	PlayerCount *regexp.Regexp
+	// Player is matched repeatedly against the List response and requires a named group "name"
+	Player *regexp.Regexp
+}
+
+// Validate the regular expressions contain the required named groups
+func (t Templates) Validate() error {
+	if len(t.List) < 1 {
+		return fmt.Errorf("missing list command")
+	}
+	if t.Player == nil {
+		return fmt.Errorf("missing player regex")
+	}
+	if t.Player.SubexpIndex("name") < 0 {
+		return fmt.Errorf("player regex %q has no named group \"name\"", t.Player)
+	}
+	if t.PlayerCount != nil && t.PlayerCount.SubexpIndex("count") < 0 {
+		return fmt.Errorf("player count regex %q has no named group \"count\"", t.PlayerCount)
+	}
+	return nil
+}
+
+// Presets for common Source RCON games, which can be overridden by configuration
+var Presets = map[string]Templates{
+	"minecraft": {
+		List:        "list",
+		Say:         "say {message}",
+		Kick:        "kick {player}",
+		Save:        "save-all",
+		Whitelist:   "whitelist add {player}",
+		Restart:     "restart",
+		PlayerCount: regexp.MustCompile(`There are (?P<count>\d+)`),
+		Player:      regexp.MustCompile(`(?:online: |, )(?P<name>[A-Za-z0-9_]+)`),
+	},
+	"rust": {
+		List:        "status",
+		Say:         "say {message}",
+		Kick:        "kick {player}",
+		Save:        "server.save",
+		PlayerCount: regexp.MustCompile(`players\s*:\s*(?P<count>\d+)`),
+		Player:      regexp.MustCompile(`(?m)^\d{17}\s+"(?P<name>[^"]*)"`),
+	},
+	"ark": {
+		List:      "ListPlayers",
+		Say:       "ServerChat {message}",
+		Kick:      "KickPlayer {player}",
+		Save:      "SaveWorld",
+		Whitelist: "AllowPlayerToJoinNoCheck {player}",
+		Player:    regexp.MustCompile(`(?m)^\d+\.\s*(?P<name>[^,]+),`),
+	},
+	"squad": {
+		List:   "ListPlayers",
+		Say:    "AdminBroadcast {message}",
+		Kick:   "AdminKick {player}",
+		Player: regexp.MustCompile(`Name: (?P<name>.+?) \|`),
+	},
+	"cs2": {
+		List:   "status",
+		Say:    "say {message}",
+		Kick:   "kick {player}",
+		Player: regexp.MustCompile(`(?m)^#\s*\d+\s+(?:\d+\s+)?"(?P<name>[^"]+)"`),
+	},
+}
+
+// render template replacing the placeholders, stripping line breaks so values
+// can't inject additional commands
+func render(template string, values map[string]string) string {
+	replacements := make([]string, 0, len(values)*2)
+	for placeholder, value := range values {
+		replacements = append(replacements, placeholder, strings.NewReplacer("\r", " ", "\n", " ").Replace(value))
+	}
+	return strings.NewReplacer(replacements...).Replace(template)
+}
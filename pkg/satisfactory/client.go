@@ -0,0 +1,219 @@
+package satisfactory
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/seibert-media/golibs/log"
+	"go.uber.org/zap"
+)
+
+// unknownPlayer is listed for the players on the server, as the API only reports their number
+const unknownPlayer = "<unknown>"
+
+// Client for the HTTPS API of a Satisfactory dedicated server
+type Client struct {
+	apiURL     string
+	token      string
+	httpClient *http.Client
+}
+
+// NewClient for the API at apiURL, e.g. https://satisfactory:7777, authenticating with
+// an application token generated using server.GenerateAPIToken in the server console
+func NewClient(apiURL, token string) Client {
+	return Client{
+		apiURL: strings.TrimSuffix(apiURL, "/"),
+		token:  token,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// WithCertificate returns a Client trusting the PEM encoded certificate, e.g. the
+// self-signed certificate the server generates on its first start
+func (c Client) WithCertificate(pem []byte) (Client, error) {
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return c, errors.New("no valid certificate found")
+	}
+	return c.withTLSConfig(&tls.Config{RootCAs: pool}), nil
+}
+
+// WithInsecureSkipVerify returns a Client accepting any certificate of the server
+func (c Client) WithInsecureSkipVerify() Client {
+	return c.withTLSConfig(&tls.Config{InsecureSkipVerify: true})
+}
+
+func (c Client) withTLSConfig(config *tls.Config) Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = config
+	c.httpClient = &http.Client{
+		Timeout:   c.httpClient.Timeout,
+		Transport: transport,
+	}
+	return c
+}
+
+// HealthCheck returns the health of the server, which is either healthy or slow
+func (c Client) HealthCheck(ctx context.Context) (string, error) {
+	var resp struct {
+		Health string `json:"health"`
+	}
+	if err := c.call(ctx, "HealthCheck", map[string]string{"clientCustomData": ""}, &resp); err != nil {
+		return "", err
+	}
+	return resp.Health, nil
+}
+
+// State of the game running on the server
+type State struct {
+	SessionName string
+	Players     int
+	PlayerLimit int
+	TechTier    int
+	Running     bool
+	Paused      bool
+	TickRate    float64
+	Duration    time.Duration
+}
+
+// State of the game running on the server
+func (c Client) State(ctx context.Context) (State, error) {
+	var resp struct {
+		ServerGameState struct {
+			ActiveSessionName   string  `json:"activeSessionName"`
+			NumConnectedPlayers int     `json:"numConnectedPlayers"`
+			PlayerLimit         int     `json:"playerLimit"`
+			TechTier            int     `json:"techTier"`
+			IsGameRunning       bool    `json:"isGameRunning"`
+			IsGamePaused        bool    `json:"isGamePaused"`
+			AverageTickRate     float64 `json:"averageTickRate"`
+			TotalGameDuration   int64   `json:"totalGameDuration"`
+		} `json:"serverGameState"`
+	}
+	if err := c.call(ctx, "QueryServerState", nil, &resp); err != nil {
+		return State{}, err
+	}
+	state := resp.ServerGameState
+	return State{
+		SessionName: state.ActiveSessionName,
+		Players:     state.NumConnectedPlayers,
+		PlayerLimit: state.PlayerLimit,
+		TechTier:    state.TechTier,
+		Running:     state.IsGameRunning,
+		Paused:      state.IsGamePaused,
+		TickRate:    state.AverageTickRate,
+		Duration:    time.Duration(state.TotalGameDuration) * time.Second,
+	}, nil
+}
+
+// CountPlayers on the server right now
+func (c Client) CountPlayers(ctx context.Context) (int, error) {
+	state, err := c.State(ctx)
+	if err != nil {
+		return -1, err
+	}
+	return state.Players, nil
+}
+
+// Players on the server right now. The API doesn't report their names, so they are listed as unknown
+func (c Client) Players(ctx context.Context) (int, []string, error) {
+	playerCount, err := c.CountPlayers(ctx)
+	if err != nil {
+		return -1, nil, err
+	}
+	if playerCount < 1 {
+		return playerCount, []string{}, nil
+	}
+	return playerCount, []string{unknownPlayer}, nil
+}
+
+// Save the game of the active session
+func (c Client) Save(ctx context.Context) error {
+	state, err := c.State(ctx)
+	if err != nil {
+		return err
+	}
+	saveName := "restart"
+	if len(state.SessionName) > 0 {
+		saveName = state.SessionName + "_restart"
+	}
+	return c.call(ctx, "SaveGame", map[string]string{"saveName": saveName}, nil)
+}
+
+// Shutdown the server
+func (c Client) Shutdown(ctx context.Context) error {
+	return c.call(ctx, "Shutdown", nil, nil)
+}
+
+// Restart the server by saving the game and shutting it down. The server does not start
+// again on its own, so it relies on the restart policy of its container
+func (c Client) Restart(ctx context.Context) error {
+	if err := c.Save(ctx); err != nil {
+		log.From(ctx).Error("saving before restart", zap.Error(err))
+	}
+	return c.Shutdown(ctx)
+}
+
+// call function of the API with data, decoding the data of the response into into if set
+func (c Client) call(ctx context.Context, function string, data interface{}, into interface{}) error {
+	request := map[string]interface{}{"function": function}
+	if data != nil {
+		request["data"] = data
+	}
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.apiURL+"/api/v1", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return errorFrom(resp)
+	}
+	if into == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	var body struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return err
+	}
+	return json.Unmarshal(body.Data, into)
+}
+
+// errorFrom builds an error from the details of a failed response
+func errorFrom(resp *http.Response) error {
+	var body struct {
+		ErrorCode    string `json:"errorCode"`
+		ErrorMessage string `json:"errorMessage"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&body); err != nil || len(body.ErrorCode) < 1 {
+		return fmt.Errorf("satisfactory responded with %s", resp.Status)
+	}
+	if len(body.ErrorMessage) < 1 {
+		return fmt.Errorf("satisfactory responded with %s: %s", resp.Status, body.ErrorCode)
+	}
+	return fmt.Errorf("satisfactory responded with %s: %s: %s", resp.Status, body.ErrorCode, body.ErrorMessage)
+}
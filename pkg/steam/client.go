@@ -0,0 +1,54 @@
+package steam
+
+import (
+	"sync"
+	"time"
+
+	a2s "github.com/rumblefrog/go-a2s"
+)
+
+// Client for talking to any server answering Steam server queries (A2S)
+type Client struct {
+	address       string
+	timeout       time.Duration
+	maxPacketSize uint32
+	a2sClient     *a2s.Client
+	// mu serializes queries, as responses are read from a shared connection
+	mu *sync.Mutex
+}
+
+// New client for the provided address
+func NewClient(address string) Client {
+	return Client{
+		address: address,
+		timeout: 1 * time.Second,
+		mu:      &sync.Mutex{},
+	}
+}
+
+// WithTimeout returns a Client using timeout for queries
+func (c Client) WithTimeout(timeout time.Duration) Client {
+	c.timeout = timeout
+	return c
+}
+
+// WithMaxPacketSize returns a Client accepting packets up to size,
+// which is required by some games using non-standard packet sizes
+func (c Client) WithMaxPacketSize(size uint32) Client {
+	c.maxPacketSize = size
+	return c
+}
+
+// Setup establishes the initial connection and authenticates the session
+func (c Client) Setup() (Client, error) {
+	options := []func(*a2s.Client) error{a2s.TimeoutOption(c.timeout)}
+	if c.maxPacketSize > 0 {
+		options = append(options, a2s.SetMaxPacketSize(c.maxPacketSize))
+	}
+	a2sClient, err := a2s.NewClient(c.address, options...)
+	if err != nil {
+		return c, err
+	}
+	c.a2sClient = a2sClient
+	return c, nil
+}
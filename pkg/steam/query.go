@@ -0,0 +1,118 @@
+package steam
+
+import (
+	"context"
+	"time"
+
+	"github.com/playnet-public/mc-bot/pkg/capability"
+	a2s "github.com/rumblefrog/go-a2s"
+)
+
+// unknownPlayer is listed for players without a name
+const unknownPlayer = "<unknown>"
+
+// Info returns an a2s ServerInfo response
+func (c Client) Info() (*a2s.ServerInfo, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.a2sClient.QueryInfo()
+}
+
+// Details queries the server info, measuring the ping of the query
+func (c Client) Details(ctx context.Context) (capability.Details, error) {
+	start := time.Now()
+	info, err := c.Info()
+	if err != nil {
+		return capability.Details{}, err
+	}
+	return capability.Details{
+		Name:       info.Name,
+		Game:       info.Game,
+		Map:        info.Map,
+		Version:    info.Version,
+		Players:    int(info.Players),
+		MaxPlayers: int(info.MaxPlayers),
+		// the visibility flag is set for servers requiring a password
+		Password: info.Visibility,
+		Ping:     time.Since(start),
+	}, nil
+}
+
+// Version of the server as reported in its info
+func (c Client) Version(ctx context.Context) (string, error) {
+	info, err := c.Info()
+	if err != nil {
+		return "", err
+	}
+	return info.Version, nil
+}
+
+// Rules the server reports, e.g. its settings and mods
+func (c Client) Rules(ctx context.Context) (map[string]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	rules, err := c.a2sClient.QueryRules()
+	if err != nil {
+		return nil, err
+	}
+	return rules.Rules, nil
+}
+
+// CountPlayers on the Server right now
+func (c Client) CountPlayers(ctx context.Context) (int, error) {
+	playerInfo, err := c.queryPlayer()
+	if err != nil {
+		return -1, err
+	}
+
+	return int(playerInfo.Count), nil
+}
+
+// PlayerList returns the number of players and the players on the server right now.
+// Names might be empty, as not all servers provide them
+func (c Client) PlayerList(ctx context.Context) (int, []capability.Player, error) {
+	playerInfo, err := c.queryPlayer()
+	if err != nil {
+		return -1, nil, err
+	}
+
+	players := make([]capability.Player, 0, len(playerInfo.Players))
+	for _, player := range playerInfo.Players {
+		players = append(players, capability.Player{
+			Name:     player.Name,
+			Score:    int(player.Score),
+			PlayTime: time.Duration(player.Duration) * time.Second,
+		})
+	}
+	return int(playerInfo.Count), players, nil
+}
+
+// Players on the server right now.
+// Players without a name are listed as unknown, as not all servers provide them
+func (c Client) Players(ctx context.Context) (int, []string, error) {
+	playerCount, players, err := c.PlayerList(ctx)
+	if err != nil {
+		return -1, nil, err
+	}
+
+	playerNames := make([]string, 0, playerCount)
+	unnamed := false
+	for _, player := range players {
+		if len(player.Name) < 1 {
+			unnamed = true
+			continue
+		}
+		playerNames = append(playerNames, player.Name)
+	}
+	if unnamed || (playerCount > 0 && len(players) < 1) {
+		playerNames = append(playerNames, unknownPlayer)
+	}
+
+	return playerCount, playerNames, nil
+}
+
+func (c Client) queryPlayer() (*a2s.PlayerInfo, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.a2sClient.QueryPlayer()
+}
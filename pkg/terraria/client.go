@@ -0,0 +1,271 @@
+package terraria
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/playnet-public/mc-bot/pkg/capability"
+	"github.com/seibert-media/golibs/log"
+	"go.uber.org/zap"
+)
+
+// errUnauthorized indicates the token was rejected, e.g. because it expired
+var errUnauthorized = errors.New("tshock rejected the token")
+
+// Client for the TShock REST API of a Terraria server
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+
+	username string
+	password string
+	// token is shared between copies of the Client, as it's created lazily when using credentials
+	token *token
+}
+
+type token struct {
+	l     sync.Mutex
+	value string
+}
+
+// NewClient for the REST API at baseURL, e.g. http://terraria:7878, authenticating with
+// an application token from the TShock config. The token may be empty if credentials are used
+func NewClient(baseURL, applicationToken string) Client {
+	return Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		token: &token{value: applicationToken},
+	}
+}
+
+// WithCredentials returns a Client creating its token with the username and password
+// of a TShock user, which is recreated once it expires
+func (c Client) WithCredentials(username, password string) Client {
+	c.username = username
+	c.password = password
+	return c
+}
+
+// WithHTTPClient returns a Client sending its requests using httpClient
+func (c Client) WithHTTPClient(httpClient *http.Client) Client {
+	c.httpClient = httpClient
+	return c
+}
+
+// Status of the server
+type Status struct {
+	Name       string
+	World      string
+	Port       int
+	Players    []string
+	MaxPlayers int
+	Uptime     string
+	Password   bool
+}
+
+// Status of the server including the names of the online players
+func (c Client) Status(ctx context.Context) (Status, error) {
+	var resp struct {
+		Name           string `json:"name"`
+		World          string `json:"world"`
+		Port           int    `json:"port"`
+		MaxPlayers     int    `json:"maxplayers"`
+		Uptime         string `json:"uptime"`
+		ServerPassword bool   `json:"serverpassword"`
+		Players        []struct {
+			Nickname string `json:"nickname"`
+		} `json:"players"`
+	}
+	if err := c.get(ctx, "/v2/server/status", url.Values{"players": {"true"}}, &resp); err != nil {
+		return Status{}, err
+	}
+
+	players := make([]string, 0, len(resp.Players))
+	for _, player := range resp.Players {
+		players = append(players, player.Nickname)
+	}
+	return Status{
+		Name:       resp.Name,
+		World:      resp.World,
+		Port:       resp.Port,
+		Players:    players,
+		MaxPlayers: resp.MaxPlayers,
+		Uptime:     resp.Uptime,
+		Password:   resp.ServerPassword,
+	}, nil
+}
+
+// CountPlayers on the server right now
+func (c Client) CountPlayers(ctx context.Context) (int, error) {
+	playerCount, _, err := c.Players(ctx)
+	return playerCount, err
+}
+
+// Players on the server right now
+func (c Client) Players(ctx context.Context) (int, []string, error) {
+	var resp struct {
+		Players []struct {
+			Nickname string `json:"nickname"`
+		} `json:"players"`
+	}
+	if err := c.get(ctx, "/v2/players/list", nil, &resp); err != nil {
+		return -1, nil, err
+	}
+
+	players := make([]string, 0, len(resp.Players))
+	for _, player := range resp.Players {
+		players = append(players, player.Nickname)
+	}
+	return len(players), players, nil
+}
+
+// SendMessage to all players on the server
+func (c Client) SendMessage(ctx context.Context, msg string) error {
+	return c.get(ctx, "/v2/server/broadcast", url.Values{"msg": {msg}}, nil)
+}
+
+// Kick player from the server
+func (c Client) Kick(ctx context.Context, player, reason string) error {
+	return c.get(ctx, "/v2/players/kick", url.Values{"player": {player}, "reason": {reason}}, nil)
+}
+
+// Ban player from the server
+func (c Client) Ban(ctx context.Context, player, reason string) error {
+	return c.get(ctx, "/v2/players/ban", url.Values{"player": {player}, "reason": {reason}}, nil)
+}
+
+// Save the world
+func (c Client) Save(ctx context.Context) error {
+	_, err := c.SendCommand(ctx, "/save")
+	return err
+}
+
+// SendCommand to the server as if it was typed into the console, e.g. "/save"
+func (c Client) SendCommand(ctx context.Context, command string) (capability.Response, error) {
+	if !strings.HasPrefix(command, "/") {
+		command = "/" + command
+	}
+	var resp struct {
+		Response []string `json:"response"`
+	}
+	if err := c.get(ctx, "/v2/server/rawcmd", url.Values{"cmd": {command}}, &resp); err != nil {
+		return capability.Response{}, err
+	}
+
+	body := strings.Join(resp.Response, "\n")
+	log.From(ctx).Info("receiving sendCommand response", zap.String("payload", body))
+
+	return capability.Response{Body: body}, nil
+}
+
+// get endpoint with params, decoding the response into into if set.
+// Tokens created from credentials are recreated once if they have been rejected
+func (c Client) get(ctx context.Context, endpoint string, params url.Values, into interface{}) error {
+	err := c.getWithToken(ctx, endpoint, params, into)
+	if errors.Is(err, errUnauthorized) && len(c.username) > 0 {
+		c.token.l.Lock()
+		c.token.value = ""
+		c.token.l.Unlock()
+		err = c.getWithToken(ctx, endpoint, params, into)
+	}
+	return err
+}
+
+func (c Client) getWithToken(ctx context.Context, endpoint string, params url.Values, into interface{}) error {
+	token, err := c.currentToken(ctx)
+	if err != nil {
+		return err
+	}
+	query := url.Values{}
+	for key, values := range params {
+		query[key] = values
+	}
+	query.Set("token", token)
+
+	raw, err := c.request(ctx, endpoint, query)
+	if err != nil {
+		return err
+	}
+	if into == nil {
+		return nil
+	}
+	return json.Unmarshal(raw, into)
+}
+
+// currentToken returns the application token or creates one using the credentials
+func (c Client) currentToken(ctx context.Context) (string, error) {
+	c.token.l.Lock()
+	defer c.token.l.Unlock()
+	if len(c.token.value) > 0 || len(c.username) < 1 {
+		return c.token.value, nil
+	}
+
+	var resp struct {
+		Token string `json:"token"`
+	}
+	raw, err := c.request(ctx, "/v2/token/create", url.Values{"username": {c.username}, "password": {c.password}})
+	if err != nil {
+		return "", fmt.Errorf("creating token: %w", err)
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return "", err
+	}
+	c.token.value = resp.Token
+	return c.token.value, nil
+}
+
+// request endpoint returning the raw body of successful responses.
+// TShock reports errors in the status of the body, often with a successful HTTP status.
+// Errors never include the URL, as its query contains the token or password
+func (c Client) request(ctx context.Context, endpoint string, query url.Values) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+endpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request for %s: %w", endpoint, withoutURL(err))
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("requesting %s: %w", endpoint, withoutURL(err))
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+
+	var envelope struct {
+		Status string `json:"status"`
+		Error  string `json:"error"`
+	}
+	if err := json.Unmarshal(raw, &envelope); err != nil {
+		return nil, fmt.Errorf("tshock responded with %s: %w", resp.Status, err)
+	}
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden ||
+		envelope.Status == "401" || envelope.Status == "403" {
+		return nil, fmt.Errorf("%w: %s", errUnauthorized, envelope.Error)
+	}
+	if resp.StatusCode != http.StatusOK || (len(envelope.Status) > 0 && envelope.Status != "200") {
+		return nil, fmt.Errorf("tshock responded with %s: %s", envelope.Status, envelope.Error)
+	}
+	return raw, nil
+}
+
+// withoutURL returns the cause of err without the URL it was wrapped with by net/http and net/url
+func withoutURL(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
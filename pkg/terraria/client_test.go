@@ -0,0 +1,187 @@
+package terraria
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// tshock serves a fake TShock REST API, accepting the application token "secret"
+// and tokens created for the user "bot" with the password "hunter2"
+type tshock struct {
+	t *testing.T
+	// valid is the token currently accepted
+	valid string
+	// created counts the tokens created using credentials
+	created int
+	// queries received per endpoint
+	queries map[string]url.Values
+}
+
+func newTShock(t *testing.T) (*tshock, string) {
+	s := &tshock{t: t, valid: "secret", queries: map[string]url.Values{}}
+	server := httptest.NewServer(s)
+	t.Cleanup(server.Close)
+	return s, server.URL
+}
+
+func (s *tshock) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	s.queries[r.URL.Path] = query
+
+	if r.URL.Path == "/v2/token/create" {
+		if query.Get("username") != "bot" || query.Get("password") != "hunter2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"status":"401","error":"Invalid username/password combination provided"}`))
+			return
+		}
+		s.created++
+		s.valid = fmt.Sprintf("token-%d", s.created)
+		_, _ = fmt.Fprintf(w, `{"status":"200","token":%q}`, s.valid)
+		return
+	}
+	if query.Get("token") != s.valid {
+		// TShock rejects tokens with a successful HTTP status
+		_, _ = w.Write([]byte(`{"status":"403","error":"Not authorized. The specified API endpoint requires a token."}`))
+		return
+	}
+
+	switch r.URL.Path {
+	case "/v2/server/status":
+		_, _ = w.Write([]byte(`{
+			"status": "200",
+			"name": "Terraria",
+			"world": "Forest",
+			"port": 7777,
+			"maxplayers": 8,
+			"uptime": "0.01:30:00",
+			"serverpassword": true,
+			"players": [{"nickname": "Guide"}, {"nickname": "Nurse"}]
+		}`))
+	case "/v2/players/list":
+		_, _ = w.Write([]byte(`{"status":"200","players":[{"nickname":"Guide"},{"nickname":"Nurse"}]}`))
+	case "/v2/server/broadcast":
+		_, _ = w.Write([]byte(`{"status":"200","response":"Successful broadcast"}`))
+	case "/v2/server/rawcmd":
+		_, _ = w.Write([]byte(`{"status":"200","response":["Saving world...","World saved."]}`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"status":"404","error":"Specified API endpoint doesn't exist."}`))
+	}
+}
+
+func TestClientStatus(t *testing.T) {
+	_, baseURL := newTShock(t)
+
+	status, err := NewClient(baseURL, "secret").Status(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Status{
+		Name:       "Terraria",
+		World:      "Forest",
+		Port:       7777,
+		Players:    []string{"Guide", "Nurse"},
+		MaxPlayers: 8,
+		Uptime:     "0.01:30:00",
+		Password:   true,
+	}
+	if !reflect.DeepEqual(status, expected) {
+		t.Errorf("expected %+v, got %+v", expected, status)
+	}
+}
+
+func TestClientPlayers(t *testing.T) {
+	_, baseURL := newTShock(t)
+
+	count, players, err := NewClient(baseURL, "secret").Players(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 || !reflect.DeepEqual(players, []string{"Guide", "Nurse"}) {
+		t.Errorf("expected Guide and Nurse, got %d %v", count, players)
+	}
+}
+
+func TestClientSendMessage(t *testing.T) {
+	server, baseURL := newTShock(t)
+
+	if err := NewClient(baseURL, "secret").SendMessage(context.Background(), "Restarting soon & saving"); err != nil {
+		t.Fatal(err)
+	}
+	if msg := server.queries["/v2/server/broadcast"].Get("msg"); msg != "Restarting soon & saving" {
+		t.Errorf("expected the message to be broadcast, got %q", msg)
+	}
+}
+
+func TestClientSendCommand(t *testing.T) {
+	server, baseURL := newTShock(t)
+
+	msg, err := NewClient(baseURL, "secret").SendCommand(context.Background(), "save")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cmd := server.queries["/v2/server/rawcmd"].Get("cmd"); cmd != "/save" {
+		t.Errorf("expected the command to be prefixed with a slash, got %q", cmd)
+	}
+	if msg.Body != "Saving world...\nWorld saved." {
+		t.Errorf("expected the response lines, got %q", msg.Body)
+	}
+}
+
+func TestClientRecreatesToken(t *testing.T) {
+	server, baseURL := newTShock(t)
+	client := NewClient(baseURL, "").WithCredentials("bot", "hunter2")
+
+	if _, _, err := client.Players(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if server.created != 1 {
+		t.Fatalf("expected a token to be created, got %d", server.created)
+	}
+
+	// the token expires, e.g. because TShock restarted
+	server.valid = "expired"
+	if _, _, err := client.Players(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if server.created != 2 {
+		t.Errorf("expected the rejected token to be recreated, got %d tokens", server.created)
+	}
+	if token := server.queries["/v2/players/list"].Get("token"); token != "token-2" {
+		t.Errorf("expected the recreated token to be used, got %q", token)
+	}
+}
+
+func TestClientErrorsHideSecrets(t *testing.T) {
+	server, baseURL := newTShock(t)
+
+	_, _, err := NewClient(baseURL, "").WithCredentials("bot", "wrong").Players(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "Invalid username/password") {
+		t.Errorf("expected the rejected credentials to be reported, got %v", err)
+	}
+	if server.created != 0 {
+		t.Errorf("expected no token to be created, got %d", server.created)
+	}
+
+	// a closed server fails in the transport, whose errors include the URL
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+	for _, client := range []Client{
+		NewClient(closed.URL, "secret"),
+		NewClient(closed.URL, "").WithCredentials("bot", "hunter2"),
+	} {
+		_, _, err := client.Players(context.Background())
+		if err == nil {
+			t.Fatal("expected an error from a closed server")
+		}
+		if strings.Contains(err.Error(), "secret") || strings.Contains(err.Error(), "hunter2") || strings.Contains(err.Error(), closed.URL) {
+			t.Errorf("expected the error not to include the URL, got %v", err)
+		}
+	}
+}
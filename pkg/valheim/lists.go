@@ -0,0 +1,148 @@
+package valheim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// List of IDs the server reads from its save directory
+type List string
+
+// Lists managed by the server
+const (
+	AdminList     List = "adminlist.txt"
+	BannedList    List = "bannedlist.txt"
+	PermittedList List = "permittedlist.txt"
+)
+
+// steamIDPattern matches SteamID64s, which Valheim uses to identify players
+var steamIDPattern = regexp.MustCompile(`^7656119[0-9]{10}$`)
+
+// ValidateSteamID checks id is a SteamID64, e.g. 76561197960287930
+func ValidateSteamID(id string) error {
+	if !steamIDPattern.MatchString(id) {
+		return fmt.Errorf("invalid steam id %s: must be a SteamID64 like 76561197960287930", id)
+	}
+	return nil
+}
+
+// IDList of a list file, keeping comments and order when edited
+type IDList struct {
+	lines []string
+}
+
+// ParseIDList from the content of a list file
+func ParseIDList(content string) IDList {
+	content = strings.TrimRight(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	if len(content) < 1 {
+		return IDList{}
+	}
+	return IDList{lines: strings.Split(content, "\n")}
+}
+
+// IDs in the order of the file
+func (l IDList) IDs() []string {
+	ids := []string{}
+	for _, line := range l.lines {
+		if id, ok := parseIDLine(line); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// Contains returns if id is in the list
+func (l IDList) Contains(id string) bool {
+	for _, existing := range l.IDs() {
+		if existing == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Add id to the end of the list if it's not in it yet
+func (l IDList) Add(id string) IDList {
+	if l.Contains(id) {
+		return l
+	}
+	lines := make([]string, len(l.lines), len(l.lines)+1)
+	copy(lines, l.lines)
+	return IDList{lines: append(lines, id)}
+}
+
+// Remove id from the list
+func (l IDList) Remove(id string) IDList {
+	lines := make([]string, 0, len(l.lines))
+	for _, line := range l.lines {
+		if existing, ok := parseIDLine(line); ok && existing == id {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return IDList{lines: lines}
+}
+
+// String returns the content of the list file
+func (l IDList) String() string {
+	if len(l.lines) < 1 {
+		return ""
+	}
+	return strings.Join(l.lines, "\n") + "\n"
+}
+
+func parseIDLine(line string) (string, bool) {
+	trimmed := strings.TrimSpace(line)
+	if len(trimmed) < 1 || strings.HasPrefix(trimmed, "//") {
+		return "", false
+	}
+	return trimmed, true
+}
+
+// ListFile stores a list in a file, e.g. on a volume shared with the server
+type ListFile struct {
+	Path string
+}
+
+// Load the content of the file, which is empty if the server did not create it yet
+func (f ListFile) Load(ctx context.Context) (string, error) {
+	content, err := os.ReadFile(f.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+// Save content by replacing the file
+func (f ListFile) Save(ctx context.Context, content string) error {
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(f.Path); err == nil {
+		mode = info.Mode()
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(f.Path), "."+filepath.Base(f.Path)+"-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.WriteString(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.Path)
+}